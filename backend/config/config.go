@@ -7,7 +7,6 @@ import (
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"github.com/nawodahansani/pos-backend/model"
 )
 
 var DB *gorm.DB
@@ -40,37 +39,3 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
-
-func ResetAndMigrateDB(db *gorm.DB) error {
-	// Drop tables in correct order (due to foreign keys)
-	db.Exec("DROP TABLE IF EXISTS order_items CASCADE")
-	db.Exec("DROP TABLE IF EXISTS orders CASCADE")
-	db.Exec("DROP TABLE IF EXISTS customers CASCADE")
-	db.Exec("DROP TABLE IF EXISTS products CASCADE")
-	db.Exec("DROP TABLE IF EXISTS users CASCADE")
-	
-	// Now migrate fresh
-	return MigrateDB(db)
-}
-
-// Add this new function for migrations
-func MigrateDB(db *gorm.DB) error {
-	// Disable constraint checks temporarily
-	db.Exec("SET CONSTRAINTS ALL DEFERRED")
-
-	// Import all your models here
-	err := db.AutoMigrate(
-		&model.User{},
-		&model.Customer{}, 
-		&model.Product{}, 
-		&model.Order{}, 
-		&model.OrderItem{},
-	)
-	if err != nil {
-		return err
-	}
-	
-	// Re-enable constraints
-	db.Exec("SET CONSTRAINTS ALL IMMEDIATE")
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration files live in config/migrations and are named
+// <version>_<name>.up.sql / <version>_<name>.down.sql, e.g. 0001_initial_schema.up.sql.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version int64
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// schemaMigration is the tracking row written for every applied migration.
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// LoadMigrations reads the embedded migration files ordered by version.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		file := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: expected .up.sql or .down.sql suffix", file)
+		}
+
+		base := strings.TrimSuffix(file, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected <version>_<name>", file)
+		}
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", file, versionStr)
+		}
+
+		body, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.UpSQL = string(body)
+		} else {
+			m.DownSQL = string(body)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		if m.DownSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+func ensureMigrationTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`).Error
+}
+
+func appliedMigrations(db *gorm.DB) (map[int64]schemaMigration, error) {
+	if err := ensureMigrationTable(db); err != nil {
+		return nil, err
+	}
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// GetMigrationStatus lists every known migration and whether it has been applied.
+func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			at := row.AppliedAt
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		list = append(list, st)
+	}
+	return list, nil
+}
+
+// CurrentVersion returns the highest applied migration version, or 0 for an empty database.
+func CurrentVersion(db *gorm.DB) (int64, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+	var current int64
+	for v := range applied {
+		if v > current {
+			current = v
+		}
+	}
+	return current, nil
+}
+
+// MigrateUp applies all pending migrations in version order.
+func MigrateUp(db *gorm.DB) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+	return MigrateTo(db, migrations[len(migrations)-1].Version)
+}
+
+// MigrateDown rolls back the given number of most recently applied migrations.
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps <= 0 {
+		return errors.New("steps must be positive")
+	}
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	return withMigrationLock(db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if err := runMigration(conn, m, false); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// MigrateTo brings the schema to exactly the given version, applying or rolling back
+// migrations as needed. Version 0 rolls back everything.
+func MigrateTo(db *gorm.DB, version int64) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	if version != 0 {
+		found := false
+		for _, m := range migrations {
+			if m.Version == version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown migration version %d", version)
+		}
+	}
+	return withMigrationLock(db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		// roll back newer migrations first, newest to oldest
+		for i := len(migrations) - 1; i >= 0; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; ok && m.Version > version {
+				if err := runMigration(conn, m, false); err != nil {
+					return err
+				}
+			}
+		}
+		// then apply anything missing up to the target, oldest to newest
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; !ok && m.Version <= version {
+				if err := runMigration(conn, m, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// migrationLockKey is the advisory lock held while migrations run, so app instances that
+// start together apply them one at a time.
+const migrationLockKey = 0x706f73 // "pos"
+
+// withMigrationLock runs fn on one connection holding the migration lock. The lock is
+// taken for the session rather than a transaction, as each migration commits on its own;
+// schema_migrations must be read after it is taken.
+func withMigrationLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("take migration lock: %w", err)
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+		return fn(conn)
+	})
+}
+
+// runMigration executes one migration and updates schema_migrations in the same transaction.
+func runMigration(db *gorm.DB, m Migration, up bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if up {
+			if err := tx.Exec(m.UpSQL).Error; err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", m.Version, m.Name, err)
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}
+		if err := tx.Exec(m.DownSQL).Error; err != nil {
+			return fmt.Errorf("migration %d_%s down: %w", m.Version, m.Name, err)
+		}
+		return tx.Delete(&schemaMigration{}, m.Version).Error
+	})
+}
+
+// ResetDB drops every table and re-applies all migrations. It wipes all data and
+// is only allowed when APP_ENV=development.
+func ResetDB(db *gorm.DB) error {
+	if getEnv("APP_ENV", "") != "development" {
+		return errors.New("reset is only allowed when APP_ENV=development")
+	}
+	if err := db.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public").Error; err != nil {
+		return err
+	}
+	return MigrateUp(db)
+}
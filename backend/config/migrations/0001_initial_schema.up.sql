@@ -0,0 +1,47 @@
+-- Baseline schema. Uses IF NOT EXISTS so databases previously created by
+-- GORM AutoMigrate can adopt the migration history without losing data.
+
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    password TEXT NOT NULL,
+    role TEXT DEFAULT 'user',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
+
+CREATE TABLE IF NOT EXISTS customers (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT,
+    email TEXT,
+    phone TEXT,
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT,
+    price DECIMAL,
+    stock BIGINT,
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT,
+    total DECIMAL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT,
+    product_id BIGINT,
+    quantity BIGINT,
+    price DECIMAL,
+    CONSTRAINT fk_orders_items FOREIGN KEY (order_id) REFERENCES orders(id)
+);
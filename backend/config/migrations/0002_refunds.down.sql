@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS refund_items;
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE order_items DROP COLUMN IF EXISTS refunded_quantity;
+ALTER TABLE orders DROP COLUMN IF EXISTS refunded_total;
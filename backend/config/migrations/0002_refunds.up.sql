@@ -0,0 +1,22 @@
+ALTER TABLE orders ADD COLUMN refunded_total DECIMAL NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN refunded_quantity BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE refunds (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    total DECIMAL NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_refunds_order_id ON refunds(order_id);
+
+CREATE TABLE refund_items (
+    id BIGSERIAL PRIMARY KEY,
+    refund_id BIGINT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    order_item_id BIGINT NOT NULL REFERENCES order_items(id),
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    amount DECIMAL NOT NULL
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_orders_status;
+DELETE FROM order_items WHERE order_id IN (SELECT id FROM orders WHERE status = 'parked');
+DELETE FROM orders WHERE status = 'parked';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS voided_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
@@ -0,0 +1,21 @@
+ALTER TABLE orders ADD COLUMN status TEXT NOT NULL DEFAULT 'completed';
+ALTER TABLE orders ADD COLUMN void_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN voided_by BIGINT REFERENCES users(id);
+ALTER TABLE orders ADD COLUMN voided_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN completed_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN updated_at TIMESTAMPTZ;
+
+-- every order before this migration was an instantly completed sale
+UPDATE orders SET completed_at = created_at, updated_at = created_at;
+
+UPDATE orders o SET status = CASE
+        WHEN NOT EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id AND i.refunded_quantity < i.quantity)
+            THEN 'refunded'
+        ELSE 'partially_refunded'
+    END
+WHERE o.refunded_total > 0;
+
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('parked', 'completed', 'voided', 'partially_refunded', 'refunded'));
+
+CREATE INDEX idx_orders_status ON orders(status);
@@ -0,0 +1,6 @@
+ALTER TABLE refund_items ALTER COLUMN amount TYPE DECIMAL;
+ALTER TABLE refunds ALTER COLUMN total TYPE DECIMAL;
+ALTER TABLE order_items ALTER COLUMN price TYPE DECIMAL;
+ALTER TABLE orders ALTER COLUMN refunded_total TYPE DECIMAL;
+ALTER TABLE orders ALTER COLUMN total TYPE DECIMAL;
+ALTER TABLE products ALTER COLUMN price TYPE DECIMAL;
@@ -0,0 +1,18 @@
+-- Money columns were unconstrained DECIMAL written from float64 values. Round them to
+-- whole cents (half away from zero, matching model.Money) and pin the scale.
+ALTER TABLE products ALTER COLUMN price TYPE NUMERIC(12,2) USING ROUND(price, 2);
+ALTER TABLE orders ALTER COLUMN total TYPE NUMERIC(12,2) USING ROUND(total, 2);
+ALTER TABLE orders ALTER COLUMN refunded_total TYPE NUMERIC(12,2) USING ROUND(refunded_total, 2);
+ALTER TABLE order_items ALTER COLUMN price TYPE NUMERIC(12,2) USING ROUND(price, 2);
+ALTER TABLE refunds ALTER COLUMN total TYPE NUMERIC(12,2) USING ROUND(total, 2);
+ALTER TABLE refund_items ALTER COLUMN amount TYPE NUMERIC(12,2) USING ROUND(amount, 2);
+
+-- order totals were summed in floating point; rebuild them from the rounded line prices
+UPDATE orders o SET total = COALESCE(
+    (SELECT SUM(i.price * i.quantity) FROM order_items i WHERE i.order_id = o.id), 0);
+UPDATE refund_items ri SET amount = oi.price * ri.quantity
+FROM order_items oi WHERE oi.id = ri.order_item_id;
+UPDATE refunds r SET total = COALESCE(
+    (SELECT SUM(ri.amount) FROM refund_items ri WHERE ri.refund_id = r.id), 0);
+UPDATE orders o SET refunded_total = COALESCE(
+    (SELECT SUM(r.total) FROM refunds r WHERE r.order_id = o.id), 0);
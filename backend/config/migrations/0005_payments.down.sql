@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payments;
+ALTER TABLE orders DROP COLUMN IF EXISTS change_due;
+ALTER TABLE orders DROP COLUMN IF EXISTS paid_total;
@@ -0,0 +1,15 @@
+ALTER TABLE orders ADD COLUMN paid_total NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN change_due NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE payments (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    tender TEXT NOT NULL CHECK (tender IN ('cash', 'card', 'store_credit', 'other')),
+    amount NUMERIC(12,2) NOT NULL,
+    tendered NUMERIC(12,2) NOT NULL,
+    change NUMERIC(12,2) NOT NULL DEFAULT 0,
+    reference TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_payments_order_id ON payments(order_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refund_tenders;
+ALTER TABLE payments DROP COLUMN IF EXISTS failure;
+ALTER TABLE payments DROP COLUMN IF EXISTS status;
+ALTER TABLE payments DROP COLUMN IF EXISTS gateway_txn_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_amount;
@@ -0,0 +1,25 @@
+ALTER TABLE payments ADD COLUMN refunded_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN gateway_txn_id TEXT NOT NULL DEFAULT '';
+-- card payments are captured after the order is committed
+ALTER TABLE payments ADD COLUMN status TEXT NOT NULL DEFAULT 'completed' CHECK (status IN ('pending', 'completed', 'failed'));
+ALTER TABLE payments ADD COLUMN failure TEXT NOT NULL DEFAULT '';
+
+-- Card refunds are sent to the gateway after the refund or void is committed, so a tender
+-- records whether that happened. Voids record their tenders without a refund.
+CREATE TABLE refund_tenders (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    refund_id BIGINT REFERENCES refunds(id) ON DELETE CASCADE,
+    payment_id BIGINT REFERENCES payments(id),
+    tender TEXT NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    gateway_txn_id TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'completed' CHECK (status IN ('pending', 'completed', 'failed')),
+    failure TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_refund_tenders_order_id ON refund_tenders(order_id);
+
+-- refunds issued before tenders were tracked were paid back in cash
+INSERT INTO refund_tenders (order_id, refund_id, tender, amount)
+SELECT order_id, id, 'cash', total FROM refunds;
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS refund_item_taxes;
+DROP TABLE IF EXISTS order_item_taxes;
+ALTER TABLE refund_items DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE refunds DROP COLUMN IF EXISTS tax_total;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_total;
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal;
+ALTER TABLE order_items DROP COLUMN IF EXISTS total;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS net_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_includes_tax;
+ALTER TABLE customers DROP COLUMN IF EXISTS tax_exempt;
+ALTER TABLE products DROP COLUMN IF EXISTS price_includes_tax;
+ALTER TABLE products DROP COLUMN IF EXISTS tax_class_id;
+DROP TABLE IF EXISTS tax_class_rates;
+DROP TABLE IF EXISTS tax_classes;
+DROP TABLE IF EXISTS tax_rates;
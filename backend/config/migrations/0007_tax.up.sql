@@ -0,0 +1,60 @@
+CREATE TABLE tax_rates (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    rate NUMERIC(9,4) NOT NULL CHECK (rate >= 0),
+    compound BOOLEAN NOT NULL DEFAULT false,
+    priority BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE tax_classes (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE tax_class_rates (
+    tax_class_id BIGINT NOT NULL REFERENCES tax_classes(id) ON DELETE CASCADE,
+    tax_rate_id BIGINT NOT NULL REFERENCES tax_rates(id) ON DELETE CASCADE,
+    PRIMARY KEY (tax_class_id, tax_rate_id)
+);
+
+ALTER TABLE products ADD COLUMN tax_class_id BIGINT REFERENCES tax_classes(id) ON DELETE SET NULL;
+ALTER TABLE products ADD COLUMN price_includes_tax BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE customers ADD COLUMN tax_exempt BOOLEAN NOT NULL DEFAULT false;
+
+-- existing sales carried no tax: net and total are the line amount
+ALTER TABLE order_items ADD COLUMN price_includes_tax BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE order_items ADD COLUMN net_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN tax_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN total NUMERIC(12,2) NOT NULL DEFAULT 0;
+UPDATE order_items SET net_amount = price * quantity, total = price * quantity;
+
+ALTER TABLE orders ADD COLUMN subtotal NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN tax_total NUMERIC(12,2) NOT NULL DEFAULT 0;
+UPDATE orders SET subtotal = total;
+
+ALTER TABLE refunds ADD COLUMN tax_total NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE refund_items ADD COLUMN tax_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE order_item_taxes (
+    id BIGSERIAL PRIMARY KEY,
+    order_item_id BIGINT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    tax_rate_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    rate NUMERIC(9,4) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_order_item_taxes_order_item_id ON order_item_taxes(order_item_id);
+
+CREATE TABLE refund_item_taxes (
+    id BIGSERIAL PRIMARY KEY,
+    refund_item_id BIGINT NOT NULL REFERENCES refund_items(id) ON DELETE CASCADE,
+    tax_rate_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    rate NUMERIC(9,4) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_refund_item_taxes_refund_item_id ON refund_item_taxes(refund_item_id);
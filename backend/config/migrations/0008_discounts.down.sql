@@ -0,0 +1,12 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_approved_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_total;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_reason;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_percent;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_type;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS order_discount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_reason;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_percent;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_type;
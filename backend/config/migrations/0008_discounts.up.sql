@@ -0,0 +1,12 @@
+ALTER TABLE order_items ADD COLUMN discount_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN discount_percent NUMERIC(9,4) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN discount_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN discount_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN order_discount NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE orders ADD COLUMN discount_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN discount_percent NUMERIC(9,4) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN discount_amount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN discount_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN discount_total NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN discount_approved_by BIGINT REFERENCES users(id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS order_item_promotions;
+ALTER TABLE orders DROP COLUMN IF EXISTS promotion_total;
+ALTER TABLE order_items DROP COLUMN IF EXISTS promotion_discount;
+DROP TABLE IF EXISTS promotion_products;
+DROP TABLE IF EXISTS promotions;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
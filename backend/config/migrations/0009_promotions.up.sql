@@ -0,0 +1,50 @@
+CREATE TABLE categories (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+ALTER TABLE products ADD COLUMN category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE TABLE promotions (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT false,
+    priority BIGINT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    daily_from TEXT NOT NULL DEFAULT '',
+    daily_to TEXT NOT NULL DEFAULT '',
+    buy_quantity BIGINT NOT NULL DEFAULT 0,
+    get_quantity BIGINT NOT NULL DEFAULT 0,
+    bundle_quantity BIGINT NOT NULL DEFAULT 0,
+    bundle_price NUMERIC(12,2) NOT NULL DEFAULT 0,
+    min_spend NUMERIC(12,2) NOT NULL DEFAULT 0,
+    percent NUMERIC(9,4) NOT NULL DEFAULT 0,
+    amount NUMERIC(12,2) NOT NULL DEFAULT 0,
+    category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_promotions_active ON promotions(active);
+
+CREATE TABLE promotion_products (
+    promotion_id BIGINT NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (promotion_id, product_id)
+);
+
+ALTER TABLE order_items ADD COLUMN promotion_discount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN promotion_total NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE order_item_promotions (
+    id BIGSERIAL PRIMARY KEY,
+    order_item_id BIGINT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    promotion_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    amount NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_order_item_promotions_order_item_id ON order_item_promotions(order_item_id);
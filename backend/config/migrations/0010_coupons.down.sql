@@ -0,0 +1,6 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS coupon_discount;
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_discount;
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_code;
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_id;
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
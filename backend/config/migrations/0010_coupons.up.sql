@@ -0,0 +1,36 @@
+CREATE TABLE coupons (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL,
+    percent NUMERIC(9,4) NOT NULL DEFAULT 0,
+    amount NUMERIC(12,2) NOT NULL DEFAULT 0,
+    min_spend NUMERIC(12,2) NOT NULL DEFAULT 0,
+    max_uses BIGINT NOT NULL DEFAULT 0,
+    used_count BIGINT NOT NULL DEFAULT 0,
+    per_customer_limit BIGINT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT false,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_coupons_used_count CHECK (max_uses = 0 OR used_count <= max_uses)
+);
+
+CREATE UNIQUE INDEX idx_coupons_code ON coupons(code);
+
+CREATE TABLE coupon_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    coupon_id BIGINT NOT NULL REFERENCES coupons(id),
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    customer_id BIGINT NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_coupon_redemptions_coupon_customer ON coupon_redemptions(coupon_id, customer_id);
+CREATE INDEX idx_coupon_redemptions_order_id ON coupon_redemptions(order_id);
+
+ALTER TABLE orders ADD COLUMN coupon_id BIGINT REFERENCES coupons(id);
+ALTER TABLE orders ADD COLUMN coupon_code TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN coupon_discount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN coupon_discount NUMERIC(12,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,33 @@
+CREATE TABLE stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    quantity BIGINT NOT NULL,
+    ref_type TEXT NOT NULL DEFAULT '',
+    ref_id BIGINT,
+    user_id BIGINT REFERENCES users(id),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_stock_movements_product_id ON stock_movements(product_id, id);
+CREATE INDEX idx_stock_movements_ref ON stock_movements(ref_type, ref_id);
+
+-- the ledger is append-only
+CREATE FUNCTION stock_movements_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+-- history before the ledger is unknown: open each product at its current stock
+INSERT INTO stock_movements (product_id, type, quantity, ref_type, ref_id, note, created_at)
+SELECT id, 'opening', COALESCE(stock, 0), 'product', id, 'balance when the ledger was introduced', now()
+FROM products
+WHERE COALESCE(stock, 0) <> 0;
+
+UPDATE products SET stock = 0 WHERE stock IS NULL;
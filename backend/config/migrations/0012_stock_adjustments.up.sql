@@ -0,0 +1 @@
+ALTER TABLE stock_movements ADD COLUMN reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,59 @@
+CREATE TABLE suppliers (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ
+);
+
+CREATE TABLE purchase_orders (
+    id BIGSERIAL PRIMARY KEY,
+    supplier_id BIGINT NOT NULL REFERENCES suppliers(id),
+    status TEXT NOT NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    total NUMERIC(12,2) NOT NULL DEFAULT 0,
+    created_by BIGINT REFERENCES users(id),
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status);
+
+CREATE TABLE purchase_order_items (
+    id BIGSERIAL PRIMARY KEY,
+    purchase_order_id BIGINT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    received_quantity BIGINT NOT NULL DEFAULT 0,
+    unit_cost NUMERIC(12,2) NOT NULL DEFAULT 0,
+    CONSTRAINT chk_purchase_order_items_received CHECK (received_quantity >= 0 AND received_quantity <= quantity)
+);
+
+CREATE INDEX idx_purchase_order_items_po ON purchase_order_items(purchase_order_id);
+
+CREATE TABLE goods_receipts (
+    id BIGSERIAL PRIMARY KEY,
+    purchase_order_id BIGINT NOT NULL REFERENCES purchase_orders(id),
+    user_id BIGINT REFERENCES users(id),
+    additional_costs NUMERIC(12,2) NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_goods_receipts_po ON goods_receipts(purchase_order_id);
+
+CREATE TABLE goods_receipt_items (
+    id BIGSERIAL PRIMARY KEY,
+    goods_receipt_id BIGINT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_item_id BIGINT NOT NULL REFERENCES purchase_order_items(id),
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    unit_cost NUMERIC(12,2) NOT NULL,
+    landed_cost NUMERIC(12,2) NOT NULL,
+    landed_unit_cost NUMERIC(12,2) NOT NULL
+);
+
+CREATE INDEX idx_goods_receipt_items_receipt ON goods_receipt_items(goods_receipt_id);
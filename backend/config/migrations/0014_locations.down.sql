@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS stock_transfer_items;
+DROP TABLE IF EXISTS stock_transfers;
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS location_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS location_id;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS location_id;
+DROP TABLE IF EXISTS stock_levels;
+DROP TABLE IF EXISTS locations;
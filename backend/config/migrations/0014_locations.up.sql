@@ -0,0 +1,64 @@
+CREATE TABLE locations (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ
+);
+
+-- at most one default location
+CREATE UNIQUE INDEX idx_locations_default ON locations(is_default) WHERE is_default;
+
+-- everything so far happened at a single store
+INSERT INTO locations (id, name, type, is_default, active, created_at)
+VALUES (1, 'Main store', 'store', true, true, now());
+SELECT setval(pg_get_serial_sequence('locations', 'id'), 1);
+
+CREATE TABLE stock_levels (
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    location_id BIGINT NOT NULL REFERENCES locations(id),
+    quantity BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (product_id, location_id),
+    CONSTRAINT chk_stock_levels_quantity CHECK (quantity >= 0)
+);
+
+CREATE INDEX idx_stock_levels_location ON stock_levels(location_id);
+
+INSERT INTO stock_levels (product_id, location_id, quantity)
+SELECT id, 1, stock FROM products WHERE stock <> 0;
+
+-- adding a column does not fire the append-only trigger
+ALTER TABLE stock_movements ADD COLUMN location_id BIGINT NOT NULL DEFAULT 1 REFERENCES locations(id);
+ALTER TABLE stock_movements ALTER COLUMN location_id DROP DEFAULT;
+
+ALTER TABLE orders ADD COLUMN location_id BIGINT NOT NULL DEFAULT 1 REFERENCES locations(id);
+ALTER TABLE orders ALTER COLUMN location_id DROP DEFAULT;
+
+ALTER TABLE purchase_orders ADD COLUMN location_id BIGINT NOT NULL DEFAULT 1 REFERENCES locations(id);
+ALTER TABLE purchase_orders ALTER COLUMN location_id DROP DEFAULT;
+
+CREATE TABLE stock_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    from_location_id BIGINT NOT NULL REFERENCES locations(id),
+    to_location_id BIGINT NOT NULL REFERENCES locations(id),
+    status TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_by BIGINT REFERENCES users(id),
+    received_by BIGINT REFERENCES users(id),
+    received_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_stock_transfers_locations CHECK (from_location_id <> to_location_id)
+);
+
+CREATE INDEX idx_stock_transfers_status ON stock_transfers(status);
+
+CREATE TABLE stock_transfer_items (
+    id BIGSERIAL PRIMARY KEY,
+    stock_transfer_id BIGINT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX idx_stock_transfer_items_transfer ON stock_transfer_items(stock_transfer_id);
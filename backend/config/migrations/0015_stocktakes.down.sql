@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_stock_movements_location;
+DROP TABLE IF EXISTS stocktake_counts;
+DROP TABLE IF EXISTS stocktake_lines;
+DROP TABLE IF EXISTS stocktakes;
@@ -0,0 +1,42 @@
+CREATE TABLE stocktakes (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES locations(id),
+    category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL,
+    status TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    ledger_mark BIGINT NOT NULL DEFAULT 0,
+    opened_by BIGINT REFERENCES users(id),
+    posted_by BIGINT REFERENCES users(id),
+    posted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_stocktakes_status ON stocktakes(status);
+
+CREATE TABLE stocktake_lines (
+    id BIGSERIAL PRIMARY KEY,
+    stocktake_id BIGINT NOT NULL REFERENCES stocktakes(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    system_quantity BIGINT NOT NULL DEFAULT 0,
+    counted BIGINT CHECK (counted >= 0),
+    count_mark BIGINT NOT NULL DEFAULT 0,
+    expected BIGINT NOT NULL DEFAULT 0,
+    variance BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_stocktake_lines_product ON stocktake_lines(stocktake_id, product_id);
+
+CREATE TABLE stocktake_counts (
+    id BIGSERIAL PRIMARY KEY,
+    stocktake_id BIGINT NOT NULL REFERENCES stocktakes(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    device TEXT NOT NULL DEFAULT '',
+    user_id BIGINT REFERENCES users(id),
+    ledger_mark BIGINT NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_stocktake_counts_stocktake ON stocktake_counts(stocktake_id);
+CREATE INDEX idx_stock_movements_location ON stock_movements(location_id, id);
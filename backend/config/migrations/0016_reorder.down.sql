@@ -0,0 +1,5 @@
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS suggested;
+DROP INDEX IF EXISTS idx_products_low_stock;
+ALTER TABLE products DROP COLUMN IF EXISTS supplier_id;
+ALTER TABLE products DROP COLUMN IF EXISTS reorder_quantity;
+ALTER TABLE products DROP COLUMN IF EXISTS reorder_point;
@@ -0,0 +1,7 @@
+ALTER TABLE products ADD COLUMN reorder_point BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN reorder_quantity BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN supplier_id BIGINT REFERENCES suppliers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_products_low_stock ON products(id) WHERE reorder_point > 0 AND stock <= reorder_point;
+
+ALTER TABLE purchase_orders ADD COLUMN suggested BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+ALTER TABLE refund_items DROP COLUMN IF EXISTS cost;
+ALTER TABLE order_items DROP COLUMN IF EXISTS cost;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS cost;
+ALTER TABLE products DROP COLUMN IF EXISTS average_cost;
+DROP TABLE IF EXISTS cost_layers;
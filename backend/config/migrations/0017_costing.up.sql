@@ -0,0 +1,23 @@
+CREATE TABLE cost_layers (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity BIGINT NOT NULL,
+    remaining BIGINT NOT NULL CHECK (remaining >= 0),
+    cost NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ref_type TEXT NOT NULL DEFAULT '',
+    ref_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_cost_layers_open ON cost_layers(product_id, id) WHERE remaining > 0;
+
+ALTER TABLE products ADD COLUMN average_cost NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE stock_movements ADD COLUMN cost NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN cost NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE refund_items ADD COLUMN cost NUMERIC(12,2) NOT NULL DEFAULT 0;
+
+-- what existing stock cost is unknown: open it as one layer at no cost
+INSERT INTO cost_layers (product_id, quantity, remaining, cost, ref_type, ref_id, created_at)
+SELECT id, stock, stock, 0, 'product', id, now()
+FROM products
+WHERE stock > 0;
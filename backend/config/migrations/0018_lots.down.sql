@@ -0,0 +1,4 @@
+ALTER TABLE goods_receipt_items DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS lot_id;
+DROP TABLE IF EXISTS lots;
+ALTER TABLE products DROP COLUMN IF EXISTS batch_tracked;
@@ -0,0 +1,18 @@
+ALTER TABLE products ADD COLUMN batch_tracked BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE lots (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id),
+    location_id BIGINT NOT NULL REFERENCES locations(id),
+    number TEXT NOT NULL,
+    expires_on DATE,
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (product_id, location_id, number)
+);
+
+CREATE INDEX idx_lots_open ON lots(product_id, location_id, expires_on) WHERE quantity > 0;
+CREATE INDEX idx_lots_expires_on ON lots(expires_on) WHERE quantity > 0;
+
+ALTER TABLE stock_movements ADD COLUMN lot_id BIGINT REFERENCES lots(id);
+ALTER TABLE goods_receipt_items ADD COLUMN lot_id BIGINT REFERENCES lots(id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS order_item_serials;
+DROP TABLE IF EXISTS serial_movements;
+DROP TABLE IF EXISTS serials;
+ALTER TABLE products DROP COLUMN IF EXISTS serial_tracked;
@@ -0,0 +1,31 @@
+ALTER TABLE products ADD COLUMN serial_tracked BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE serials (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id),
+    number TEXT NOT NULL,
+    status TEXT NOT NULL,
+    location_id BIGINT REFERENCES locations(id),
+    order_id BIGINT REFERENCES orders(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (product_id, number)
+);
+
+CREATE INDEX idx_serials_number ON serials(number);
+
+CREATE TABLE serial_movements (
+    serial_id BIGINT NOT NULL REFERENCES serials(id),
+    stock_movement_id BIGINT NOT NULL REFERENCES stock_movements(id),
+    PRIMARY KEY (serial_id, stock_movement_id)
+);
+
+CREATE INDEX idx_serial_movements_movement ON serial_movements(stock_movement_id);
+
+CREATE TABLE order_item_serials (
+    id BIGSERIAL PRIMARY KEY,
+    order_item_id BIGINT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    number TEXT NOT NULL
+);
+
+CREATE INDEX idx_order_item_serials_order_item_id ON order_item_serials(order_item_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS barcodes;
+DROP INDEX IF EXISTS idx_products_sku;
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
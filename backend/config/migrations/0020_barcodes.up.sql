@@ -0,0 +1,13 @@
+ALTER TABLE products ADD COLUMN sku TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_products_sku ON products(sku) WHERE sku <> '';
+
+CREATE TABLE barcodes (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    type TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_barcodes_code ON barcodes(code);
+CREATE INDEX idx_barcodes_product_id ON barcodes(product_id);
@@ -0,0 +1,5 @@
+ALTER TABLE categories ADD COLUMN parent_id BIGINT REFERENCES categories(id);
+ALTER TABLE categories ADD COLUMN tax_class_id BIGINT REFERENCES tax_classes(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX idx_products_category_id ON products(category_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS variant_option_values;
+DROP TABLE IF EXISTS product_option_values;
+DROP TABLE IF EXISTS product_options;
+DROP INDEX IF EXISTS idx_products_parent_id;
+ALTER TABLE products DROP COLUMN IF EXISTS price_override;
+ALTER TABLE products DROP COLUMN IF EXISTS has_variants;
+ALTER TABLE products DROP COLUMN IF EXISTS parent_id;
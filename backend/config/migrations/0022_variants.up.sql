@@ -0,0 +1,29 @@
+ALTER TABLE products ADD COLUMN parent_id BIGINT REFERENCES products(id);
+ALTER TABLE products ADD COLUMN has_variants BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE products ADD COLUMN price_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_products_parent_id ON products(parent_id);
+
+CREATE TABLE product_options (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    position INT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_product_options_name ON product_options(product_id, lower(name));
+
+CREATE TABLE product_option_values (
+    id BIGSERIAL PRIMARY KEY,
+    option_id BIGINT NOT NULL REFERENCES product_options(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    position INT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_product_option_values_value ON product_option_values(option_id, lower(value));
+
+CREATE TABLE variant_option_values (
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    option_value_id BIGINT NOT NULL REFERENCES product_option_values(id),
+    PRIMARY KEY (product_id, option_value_id)
+);
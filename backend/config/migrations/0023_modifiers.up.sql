@@ -0,0 +1,38 @@
+CREATE TABLE modifier_groups (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    min_select INT NOT NULL DEFAULT 0,
+    max_select INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE modifiers (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    price_delta NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ingredient_id BIGINT REFERENCES products(id),
+    ingredient_quantity INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_modifiers_group_id ON modifiers(group_id);
+
+CREATE TABLE product_modifier_groups (
+    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    modifier_group_id BIGINT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, modifier_group_id)
+);
+
+-- modifier_id is kept without a foreign key so sold lines outlive the modifiers they used
+CREATE TABLE order_item_modifiers (
+    id BIGSERIAL PRIMARY KEY,
+    order_item_id BIGINT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    modifier_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    price_delta NUMERIC(12,2) NOT NULL DEFAULT 0,
+    ingredient_id BIGINT REFERENCES products(id),
+    ingredient_quantity INT NOT NULL DEFAULT 0,
+    cost NUMERIC(12,2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_order_item_modifiers_order_item_id ON order_item_modifiers(order_item_id);
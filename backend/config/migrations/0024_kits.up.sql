@@ -0,0 +1,20 @@
+ALTER TABLE products ADD COLUMN is_kit BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE kit_components (
+    id BIGSERIAL PRIMARY KEY,
+    kit_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_id BIGINT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0)
+);
+
+CREATE UNIQUE INDEX idx_kit_components_kit_component ON kit_components(kit_id, component_id);
+
+CREATE TABLE order_item_components (
+    id BIGSERIAL PRIMARY KEY,
+    order_item_id BIGINT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL,
+    cost NUMERIC(12,2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_order_item_components_order_item_id ON order_item_components(order_item_id);
@@ -0,0 +1,32 @@
+ALTER TABLE goods_receipt_items DROP COLUMN IF EXISTS stock_quantity;
+ALTER TABLE purchase_order_items DROP COLUMN IF EXISTS pack_size;
+ALTER TABLE products DROP COLUMN IF EXISTS pack_size;
+ALTER TABLE products DROP COLUMN IF EXISTS purchase_unit;
+ALTER TABLE products DROP COLUMN IF EXISTS "precision";
+ALTER TABLE products DROP COLUMN IF EXISTS unit;
+
+-- fractions of a unit are lost
+ALTER TABLE order_item_components ALTER COLUMN quantity TYPE INT USING ROUND(quantity);
+ALTER TABLE kit_components ALTER COLUMN quantity TYPE INT USING ROUND(quantity);
+ALTER TABLE order_item_modifiers ALTER COLUMN ingredient_quantity TYPE INT USING ROUND(ingredient_quantity);
+ALTER TABLE modifiers ALTER COLUMN ingredient_quantity TYPE INT USING ROUND(ingredient_quantity);
+ALTER TABLE lots ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE cost_layers ALTER COLUMN remaining TYPE BIGINT USING ROUND(remaining);
+ALTER TABLE cost_layers ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE stocktake_counts ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE stocktake_lines ALTER COLUMN variance TYPE BIGINT USING ROUND(variance);
+ALTER TABLE stocktake_lines ALTER COLUMN expected TYPE BIGINT USING ROUND(expected);
+ALTER TABLE stocktake_lines ALTER COLUMN counted TYPE BIGINT USING ROUND(counted);
+ALTER TABLE stocktake_lines ALTER COLUMN system_quantity TYPE BIGINT USING ROUND(system_quantity);
+ALTER TABLE stock_transfer_items ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE stock_levels ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE goods_receipt_items ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE purchase_order_items ALTER COLUMN received_quantity TYPE BIGINT USING ROUND(received_quantity);
+ALTER TABLE purchase_order_items ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE stock_movements ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE refund_items ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE order_items ALTER COLUMN refunded_quantity TYPE BIGINT USING ROUND(refunded_quantity);
+ALTER TABLE order_items ALTER COLUMN quantity TYPE BIGINT USING ROUND(quantity);
+ALTER TABLE products ALTER COLUMN reorder_quantity TYPE BIGINT USING ROUND(reorder_quantity);
+ALTER TABLE products ALTER COLUMN reorder_point TYPE BIGINT USING ROUND(reorder_point);
+ALTER TABLE products ALTER COLUMN stock TYPE BIGINT USING ROUND(stock);
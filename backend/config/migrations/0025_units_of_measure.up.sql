@@ -0,0 +1,39 @@
+-- Quantities were whole units. Keep three decimal places so products can be sold by
+-- weight, volume or length; model.Quantity holds them as thousandths.
+ALTER TABLE products ALTER COLUMN stock TYPE NUMERIC(14,3);
+ALTER TABLE products ALTER COLUMN reorder_point TYPE NUMERIC(14,3);
+ALTER TABLE products ALTER COLUMN reorder_quantity TYPE NUMERIC(14,3);
+ALTER TABLE order_items ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE order_items ALTER COLUMN refunded_quantity TYPE NUMERIC(14,3);
+ALTER TABLE refund_items ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_movements ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE purchase_order_items ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE purchase_order_items ALTER COLUMN received_quantity TYPE NUMERIC(14,3);
+ALTER TABLE goods_receipt_items ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_levels ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_transfer_items ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stocktake_lines ALTER COLUMN system_quantity TYPE NUMERIC(14,3);
+ALTER TABLE stocktake_lines ALTER COLUMN counted TYPE NUMERIC(14,3);
+ALTER TABLE stocktake_lines ALTER COLUMN expected TYPE NUMERIC(14,3);
+ALTER TABLE stocktake_lines ALTER COLUMN variance TYPE NUMERIC(14,3);
+ALTER TABLE stocktake_counts ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE cost_layers ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE cost_layers ALTER COLUMN remaining TYPE NUMERIC(14,3);
+ALTER TABLE lots ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE modifiers ALTER COLUMN ingredient_quantity TYPE NUMERIC(14,3);
+ALTER TABLE order_item_modifiers ALTER COLUMN ingredient_quantity TYPE NUMERIC(14,3);
+ALTER TABLE kit_components ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE order_item_components ALTER COLUMN quantity TYPE NUMERIC(14,3);
+
+ALTER TABLE products ADD COLUMN unit TEXT NOT NULL DEFAULT 'each';
+ALTER TABLE products ADD COLUMN "precision" INT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN purchase_unit TEXT NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN pack_size NUMERIC(14,3) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD CONSTRAINT chk_products_unit CHECK (unit IN ('each', 'kg', 'g', 'l', 'm'));
+ALTER TABLE products ADD CONSTRAINT chk_products_precision CHECK ("precision" BETWEEN 0 AND 3);
+ALTER TABLE products ADD CONSTRAINT chk_products_pack_size CHECK (pack_size >= 0);
+
+-- lines ordered so far were in the product's own unit
+ALTER TABLE purchase_order_items ADD COLUMN pack_size NUMERIC(14,3) NOT NULL DEFAULT 1 CHECK (pack_size > 0);
+ALTER TABLE goods_receipt_items ADD COLUMN stock_quantity NUMERIC(14,3) NOT NULL DEFAULT 0;
+UPDATE goods_receipt_items SET stock_quantity = quantity;
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/service"
+)
+
+type CategoryController struct {
+	svc service.CategoryService
+}
+
+func NewCategoryController(s service.CategoryService) *CategoryController {
+	return &CategoryController{svc: s}
+}
+
+func (c *CategoryController) Create(ctx *gin.Context) {
+	var input dto.CreateCategoryDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+	cat, err := c.svc.CreateCategory(input)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "create failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "created", cat})
+}
+
+func (c *CategoryController) List(ctx *gin.Context) {
+	list, err := c.svc.List()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+// Tree lists the categories nested under their parents.
+func (c *CategoryController) Tree(ctx *gin.Context) {
+	list, err := c.svc.Tree()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+// ListProducts lists the products in the category and all of its subcategories.
+func (c *CategoryController) ListProducts(ctx *gin.Context) {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	list, err := c.svc.ListProducts(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *CategoryController) GetByID(ctx *gin.Context) {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	cat, err := c.svc.GetByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", cat})
+}
+
+func (c *CategoryController) Update(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.CreateCategoryDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	cat, err := c.svc.UpdateCategory(uint(id), input)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "update failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "updated", cat})
+}
+
+func (c *CategoryController) Delete(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	if err := c.svc.DeleteCategory(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "delete failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "deleted", nil})
+}
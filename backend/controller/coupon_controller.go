@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/service"
+)
+
+type CouponController struct {
+	svc service.CouponService
+}
+
+func NewCouponController(s service.CouponService) *CouponController {
+	return &CouponController{svc: s}
+}
+
+func (c *CouponController) Create(ctx *gin.Context) {
+	var input dto.CreateCouponDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+	cp, err := c.svc.CreateCoupon(input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "create failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "created", cp})
+}
+
+func (c *CouponController) Generate(ctx *gin.Context) {
+	var input dto.GenerateCouponsDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+	list, err := c.svc.GenerateCoupons(input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "generate failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "created", list})
+}
+
+func (c *CouponController) List(ctx *gin.Context) {
+	list, err := c.svc.ListCoupons()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *CouponController) GetByID(ctx *gin.Context) {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	cp, err := c.svc.GetCoupon(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", cp})
+}
+
+func (c *CouponController) Update(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.CreateCouponDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	cp, err := c.svc.UpdateCoupon(uint(id), input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "update failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "updated", cp})
+}
+
+func (c *CouponController) Delete(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	if err := c.svc.DeleteCoupon(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "delete failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "deleted", nil})
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/service"
+)
+
+type InventoryController struct {
+	svc        service.InventoryService
+	reorderSvc service.ReorderService
+}
+
+func NewInventoryController(s service.InventoryService, rs service.ReorderService) *InventoryController {
+	return &InventoryController{svc: s, reorderSvc: rs}
+}
+
+func (c *InventoryController) AdjustStock(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.StockAdjustmentDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	m, err := c.svc.AdjustStock(uint(id), input, ctx.GetUint("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "adjustment failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "stock adjusted", m})
+}
+
+func (c *InventoryController) ListMovements(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	list, err := c.svc.ListMovements(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *InventoryController) ListLevels(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	list, err := c.svc.ListLevels(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *InventoryController) ListLots(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	list, err := c.svc.ListLots(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+// ExpiringLots lists lots expiring within ?days (30 by default), including expired ones,
+// optionally at one ?location_id.
+func (c *InventoryController) ExpiringLots(ctx *gin.Context) {
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "30"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid days", err.Error()})
+		return
+	}
+	locationID, _ := strconv.Atoi(ctx.Query("location_id"))
+	list, err := c.svc.ExpiringLots(days, uint(locationID))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "list failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *InventoryController) ReconcileProduct(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", ctx.Param("id")})
+		return
+	}
+	list, err := c.svc.Reconcile(uint(id))
+	if err != nil || len(list) == 0 {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", nil})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list[0]})
+}
+
+// Reconcile lists every product; ?mismatched=true keeps only those whose stock differs
+// from the ledger.
+func (c *InventoryController) Reconcile(ctx *gin.Context) {
+	list, err := c.svc.Reconcile(0)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "reconcile failed", err.Error()})
+		return
+	}
+	if ctx.Query("mismatched") == "true" {
+		var mismatched = list[:0]
+		for _, r := range list {
+			if r.Difference != 0 {
+				mismatched = append(mismatched, r)
+			}
+		}
+		list = mismatched
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *InventoryController) LowStock(ctx *gin.Context) {
+	list, err := c.reorderSvc.LowStock()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+// SuggestReorders runs the reorder job now and returns the draft purchase orders it raised.
+func (c *InventoryController) SuggestReorders(ctx *gin.Context) {
+	result, err := c.reorderSvc.GenerateSuggestions(ctx.GetUint("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "reorder failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", result})
+}
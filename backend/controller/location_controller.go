@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/service"
+)
+
+type LocationController struct {
+	svc service.LocationService
+}
+
+func NewLocationController(s service.LocationService) *LocationController {
+	return &LocationController{svc: s}
+}
+
+func (c *LocationController) Create(ctx *gin.Context) {
+	var input dto.CreateLocationDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+	loc, err := c.svc.CreateLocation(input)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "create failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "created", loc})
+}
+
+func (c *LocationController) List(ctx *gin.Context) {
+	list, err := c.svc.List()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *LocationController) GetByID(ctx *gin.Context) {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	loc, err := c.svc.GetByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", loc})
+}
+
+func (c *LocationController) Update(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.CreateLocationDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	loc, err := c.svc.UpdateLocation(uint(id), input)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "update failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "updated", loc})
+}
+
+func (c *LocationController) Delete(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	if err := c.svc.DeleteLocation(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "delete failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "deleted", nil})
+}
+
+func (c *LocationController) ListStock(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	list, err := c.svc.ListStock(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/service"
+)
+
+type ModifierController struct {
+	svc service.ModifierService
+}
+
+func NewModifierController(s service.ModifierService) *ModifierController {
+	return &ModifierController{svc: s}
+}
+
+func (c *ModifierController) CreateGroup(ctx *gin.Context) {
+	var input dto.CreateModifierGroupDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+	g, err := c.svc.CreateGroup(input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "create failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "created", g})
+}
+
+func (c *ModifierController) ListGroups(ctx *gin.Context) {
+	list, err := c.svc.ListGroups()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *ModifierController) GetGroup(ctx *gin.Context) {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	g, err := c.svc.GetGroup(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", g})
+}
+
+func (c *ModifierController) UpdateGroup(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.CreateModifierGroupDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	g, err := c.svc.UpdateGroup(uint(id), input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "update failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "updated", g})
+}
+
+func (c *ModifierController) DeleteGroup(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	if err := c.svc.DeleteGroup(uint(id)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "delete failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "deleted", nil})
+}
+
+func (c *ModifierController) ListByProduct(ctx *gin.Context) {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	list, err := c.svc.ListByProduct(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *ModifierController) SetProductGroups(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.SetModifierGroupsDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	list, err := c.svc.SetProductGroups(uint(id), input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "update failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "updated", list})
+}
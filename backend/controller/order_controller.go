@@ -29,7 +29,7 @@ func (c *OrderController) CreateOrder(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
 		return
 	}
-	o, err := c.svc.CreateOrder(input)
+	o, err := c.svc.CreateOrder(input, ctx.GetUint("userID"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "create order failed", err.Error()})
 		return
@@ -49,10 +49,123 @@ func (c *OrderController) GetOrder(ctx *gin.Context) {
 }
 
 func (c *OrderController) ListOrders(ctx *gin.Context) {
-	list, err := c.svc.ListOrders()
+	list, err := c.svc.ListOrders(ctx.Query("status"))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
 }
+
+func (c *OrderController) RefundOrder(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.CreateRefundDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	userID := ctx.GetUint("userID")
+	refund, err := c.svc.RefundOrder(uint(id), input, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "refund failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "refund created", refund})
+}
+
+func (c *OrderController) ListRefunds(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	list, err := c.svc.ListRefunds(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *OrderController) ParkOrder(ctx *gin.Context) {
+	var input dto.CreateOrderDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+	o, err := c.svc.ParkOrder(input, ctx.GetUint("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "park order failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "order parked", o})
+}
+
+func (c *OrderController) UpdateParkedOrder(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.CreateOrderDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	o, err := c.svc.UpdateParkedOrder(uint(id), input, ctx.GetUint("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "update failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "updated", o})
+}
+
+func (c *OrderController) ResumeOrder(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.CompleteOrderDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	o, err := c.svc.ResumeOrder(uint(id), input, ctx.GetUint("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "resume failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "order completed", o})
+}
+
+func (c *OrderController) VoidOrder(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.VoidOrderDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	o, err := c.svc.VoidOrder(uint(id), input, ctx.GetUint("userID"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "void failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "order voided", o})
+}
@@ -31,7 +31,7 @@ func (c *ProductController) CreateProduct(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
 		return
 	}
-	p, err := c.svc.CreateProduct(input)
+	p, err := c.svc.CreateProduct(input, ctx.GetUint("userID"))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "create failed", err.Error()})
 		return
@@ -39,8 +39,10 @@ func (c *ProductController) CreateProduct(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "created", p})
 }
 
+// List takes an optional location_id to list only the products stocked there.
 func (c *ProductController) List(ctx *gin.Context) {
-	list, err := c.svc.List()
+	locationID, _ := strconv.Atoi(ctx.Query("location_id"))
+	list, err := c.svc.List(uint(locationID))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "list failed", err.Error()})
 		return
@@ -59,6 +61,16 @@ func (c *ProductController) GetByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", p})
 }
 
+// GetByBarcode is the till's scan lookup.
+func (c *ProductController) GetByBarcode(ctx *gin.Context) {
+	p, err := c.svc.GetByBarcode(ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", p})
+}
+
 func (c *ProductController) UpdateProduct(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -67,7 +79,7 @@ func (c *ProductController) UpdateProduct(ctx *gin.Context) {
 		return
 	}
 
-	var input dto.CreateProductDTO
+	var input dto.UpdateProductDTO
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
 		return
@@ -96,4 +108,34 @@ func (c *ProductController) DeleteProduct(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "deleted", nil})
-}
\ No newline at end of file
+}
+func (c *ProductController) GenerateVariants(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+
+	var input dto.GenerateVariantsDTO
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid input", err.Error()})
+		return
+	}
+
+	p, err := c.svc.GenerateVariants(uint(id), input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "generate variants failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "variants generated", p})
+}
+
+func (c *ProductController) ListVariants(ctx *gin.Context) {
+	id, _ := strconv.Atoi(ctx.Param("id"))
+	variants, err := c.svc.ListVariants(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", variants})
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/service"
+)
+
+type ReportController struct {
+	svc service.ReportService
+}
+
+func NewReportController(s service.ReportService) *ReportController {
+	return &ReportController{svc: s}
+}
+
+// parseDateRange reads optional ?from=YYYY-MM-DD&to=YYYY-MM-DD; "to" is inclusive of that day.
+func parseDateRange(ctx *gin.Context) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if v := ctx.Query("from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &t
+	}
+	if v := ctx.Query("to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, nil, err
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+	return from, to, nil
+}
+
+func (c *ReportController) SalesSummary(ctx *gin.Context) {
+	from, to, err := parseDateRange(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid date range", err.Error()})
+		return
+	}
+	summary, err := c.svc.SalesSummary(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "report failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", summary})
+}
+
+func (c *ReportController) PaymentsByTender(ctx *gin.Context) {
+	from, to, err := parseDateRange(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid date range", err.Error()})
+		return
+	}
+	list, err := c.svc.PaymentsByTender(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "report failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *ReportController) TaxByRate(ctx *gin.Context) {
+	from, to, err := parseDateRange(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid date range", err.Error()})
+		return
+	}
+	list, err := c.svc.TaxByRate(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "report failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *ReportController) Promotions(ctx *gin.Context) {
+	from, to, err := parseDateRange(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid date range", err.Error()})
+		return
+	}
+	list, err := c.svc.Promotions(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "report failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+func (c *ReportController) GrossProfit(ctx *gin.Context) {
+	from, to, err := parseDateRange(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid date range", err.Error()})
+		return
+	}
+	list, err := c.svc.GrossProfit(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "report failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+// SalesByCategory totals each category with its subcategories.
+func (c *ReportController) SalesByCategory(ctx *gin.Context) {
+	from, to, err := parseDateRange(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid date range", err.Error()})
+		return
+	}
+	list, err := c.svc.SalesByCategory(from, to)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.ResponseDTO{"error", "report failed", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/service"
+)
+
+type SerialController struct {
+	svc service.SerialService
+}
+
+func NewSerialController(s service.SerialService) *SerialController {
+	return &SerialController{svc: s}
+}
+
+// History returns every product's serial with the number and what happened to it:
+// received, sold and to whom, returned, moved.
+func (c *SerialController) History(ctx *gin.Context) {
+	list, err := c.svc.History(ctx.Param("number"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
+
+// ListByProduct takes an optional ?status, e.g. in_stock.
+func (c *SerialController) ListByProduct(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ResponseDTO{"error", "invalid id", err.Error()})
+		return
+	}
+	list, err := c.svc.ListByProduct(uint(id), ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, dto.ResponseDTO{"error", "not found", err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ResponseDTO{"success", "ok", list})
+}
@@ -0,0 +1,8 @@
+package dto
+
+// CreateCategoryDTO.ParentID places the category under another; it is top-level without.
+type CreateCategoryDTO struct {
+	Name       string `json:"name" binding:"required"`
+	ParentID   *uint  `json:"parent_id"`
+	TaxClassID *uint  `json:"tax_class_id"`
+}
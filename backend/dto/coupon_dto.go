@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+// CreateCouponDTO creates one coupon. A code is generated when none is given; codes are
+// matched without regard to case.
+type CreateCouponDTO struct {
+	Code             string        `json:"code" binding:"omitempty,alphanum,max=32"`
+	Description      string        `json:"description"`
+	Type             string        `json:"type" binding:"required,oneof=percent fixed"`
+	Percent          model.Percent `json:"percent" binding:"min=0,max=1000000"`
+	Amount           model.Money   `json:"amount" binding:"min=0"`
+	MinSpend         model.Money   `json:"min_spend" binding:"min=0"`
+	MaxUses          int           `json:"max_uses" binding:"min=0"`
+	PerCustomerLimit int           `json:"per_customer_limit" binding:"min=0"`
+	Active           bool          `json:"active"`
+	ExpiresAt        *time.Time    `json:"expires_at"`
+}
+
+// GenerateCouponsDTO creates count coupons with the same settings and generated codes
+// starting with prefix. The code field is ignored.
+type GenerateCouponsDTO struct {
+	Count  int    `json:"count" binding:"required,min=1,max=1000"`
+	Prefix string `json:"prefix" binding:"omitempty,alphanum,max=12"`
+	CreateCouponDTO
+}
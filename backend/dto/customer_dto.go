@@ -4,4 +4,6 @@ type CreateCustomerDTO struct {
 	Name  string `json:"name" binding:"required"`
 	Email string `json:"email"`
 	Phone string `json:"phone"`
+	// TaxExempt customers are charged no tax; tax-inclusive prices are reduced by the tax they contain
+	TaxExempt bool `json:"tax_exempt"`
 }
@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type CreateLocationDTO struct {
+	Name      string `json:"name" binding:"required"`
+	Type      string `json:"type" binding:"required,oneof=store warehouse"`
+	IsDefault bool   `json:"is_default"`
+	Active    bool   `json:"active"`
+}
+
+// StockTransferItemDTO.Serials are the serials sent, one per unit, for a serial-tracked
+// product.
+type StockTransferItemDTO struct {
+	ProductID uint           `json:"product_id" binding:"required"`
+	Quantity  model.Quantity `json:"quantity" binding:"required,gt=0"`
+	Serials   []string       `json:"serials"`
+}
+
+type CreateStockTransferDTO struct {
+	FromLocationID uint                   `json:"from_location_id" binding:"required"`
+	ToLocationID   uint                   `json:"to_location_id" binding:"required"`
+	Note           string                 `json:"note"`
+	Items          []StockTransferItemDTO `json:"items" binding:"required,min=1,dive"`
+}
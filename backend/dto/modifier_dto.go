@@ -0,0 +1,28 @@
+package dto
+
+import "github.com/nawodahansani/pos-backend/model"
+
+// CreateModifierGroupDTO.MaxSelect of 0 sets no upper limit. On update, modifiers with an
+// ID are kept and changed, those without are added and the group's other modifiers are
+// removed.
+type CreateModifierGroupDTO struct {
+	Name      string        `json:"name" binding:"required"`
+	MinSelect int           `json:"min_select" binding:"min=0"`
+	MaxSelect int           `json:"max_select" binding:"min=0"`
+	Modifiers []ModifierDTO `json:"modifiers" binding:"required,min=1,dive"`
+}
+
+// ModifierDTO.IngredientQuantity is what one unit made with the modifier uses of the
+// IngredientID product.
+type ModifierDTO struct {
+	ID                 uint           `json:"id"`
+	Name               string         `json:"name" binding:"required"`
+	PriceDelta         model.Money    `json:"price_delta"`
+	IngredientID       *uint          `json:"ingredient_id"`
+	IngredientQuantity model.Quantity `json:"ingredient_quantity" binding:"min=0"`
+}
+
+// SetModifierGroupsDTO replaces the modifier groups offered on a product.
+type SetModifierGroupsDTO struct {
+	GroupIDs []uint `json:"group_ids"`
+}
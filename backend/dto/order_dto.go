@@ -1,11 +1,60 @@
 package dto
 
+import "github.com/nawodahansani/pos-backend/model"
+
+// OrderItemDTO names the product by ProductID, VariantID or a scanned Barcode. Quantity
+// may be left out for a scale barcode, which carries it.
 type OrderItemDTO struct {
-	ProductID uint `json:"product_id" binding:"required"`
-	Quantity  int  `json:"quantity" binding:"required"`
+	ProductID uint           `json:"product_id" binding:"required_without_all=Barcode VariantID"`
+	VariantID uint           `json:"variant_id"`
+	Barcode   string         `json:"barcode"`
+	Quantity  model.Quantity `json:"quantity"`
+	Discount  *DiscountDTO   `json:"discount"`
+	Serials   []string       `json:"serials"`
+	Modifiers []uint         `json:"modifiers"`
+}
+
+// DiscountDTO is a line or order discount. Percent is used for type percent, Amount for
+// type fixed.
+type DiscountDTO struct {
+	Type    string        `json:"type" binding:"required,oneof=percent fixed"`
+	Percent model.Percent `json:"percent" binding:"min=0,max=1000000"`
+	Amount  model.Money   `json:"amount" binding:"min=0"`
+	Reason  string        `json:"reason" binding:"required,oneof=damaged price_match loyalty staff other"`
 }
 
+// DiscountApprovalDTO holds the credentials of the manager or admin approving a discount
+// above the approval threshold, entered at the till. An admin ringing up the order needs
+// none.
+type DiscountApprovalDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type PaymentDTO struct {
+	Tender    string      `json:"tender" binding:"required,oneof=cash card store_credit other"`
+	Amount    model.Money `json:"amount" binding:"required,gt=0"`
+	Reference string      `json:"reference"`
+}
+
+// Payments are required to complete an order and ignored when parking one. LocationID is
+// where stock is taken from, the default location when omitted.
 type CreateOrderDTO struct {
-	CustomerID uint           `json:"customer_id" binding:"required"`
-	Items      []OrderItemDTO `json:"items" binding:"required"`
+	CustomerID uint                 `json:"customer_id" binding:"required"`
+	LocationID uint                 `json:"location_id"`
+	Items      []OrderItemDTO       `json:"items" binding:"required,min=1,dive"`
+	Discount   *DiscountDTO         `json:"discount"`
+	Approval   *DiscountApprovalDTO `json:"approval"`
+	CouponCode string               `json:"coupon_code"`
+	Payments   []PaymentDTO         `json:"payments" binding:"dive"`
+}
+
+// Approval is only needed if the parked order's discounts were never approved.
+type CompleteOrderDTO struct {
+	Approval *DiscountApprovalDTO `json:"approval"`
+	Payments []PaymentDTO         `json:"payments" binding:"dive"`
+}
+
+type VoidOrderDTO struct {
+	Reason string `json:"reason" binding:"required"`
 }
@@ -1,14 +1,107 @@
 package dto
 
+import "github.com/nawodahansani/pos-backend/model"
+
+// LotDTO names the lot stock of a batch-tracked product comes in as. ExpiresOn is a date,
+// e.g. 2026-03-31.
+type LotDTO struct {
+	Number    string `json:"number" binding:"required"`
+	ExpiresOn string `json:"expires_on" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// BarcodeDTO.Code is checked against Type, including the check digit of EAN-13 and UPC-A
+// codes.
+type BarcodeDTO struct {
+	Code string `json:"code" binding:"required"`
+	Type string `json:"type" binding:"required,oneof=ean13 upca code128 scale"`
+}
+
+// UnitDTO is the unit a product is sold in, each when omitted. PackSize is how many units
+// come in the PurchaseUnit it is ordered in, e.g. 12 to a case.
+type UnitDTO struct {
+	Unit         string         `json:"unit" binding:"omitempty,oneof=each kg g l m"`
+	Precision    int            `json:"precision" binding:"min=0,max=3"`
+	PurchaseUnit string         `json:"purchase_unit" binding:"max=32"`
+	PackSize     model.Quantity `json:"pack_size" binding:"min=0"`
+}
+
+// KitComponentDTO is a line of a kit's bill of materials: Quantity units of ProductID go
+// into every kit.
+type KitComponentDTO struct {
+	ProductID uint           `json:"product_id" binding:"required"`
+	Quantity  model.Quantity `json:"quantity" binding:"required,gt=0"`
+}
+
+// CreateProductDTO.Stock is the opening stock at LocationID, or the default location, at
+// UnitCost a unit. A product given Components is a kit and has no stock of its own.
 type CreateProductDTO struct {
-	Name  string  `json:"name" binding:"required"`
-	Price float64 `json:"price" binding:"required"`
-	Stock int     `json:"stock" binding:"required"`
+	Name             string            `json:"name" binding:"required"`
+	SKU              string            `json:"sku" binding:"max=64"`
+	Barcodes         []BarcodeDTO      `json:"barcodes" binding:"dive"`
+	CategoryID       *uint             `json:"category_id"`
+	Price            model.Money       `json:"price" binding:"required"`
+	PriceIncludesTax bool              `json:"price_includes_tax"`
+	TaxClassID       *uint             `json:"tax_class_id"`
+	Stock            model.Quantity    `json:"stock" binding:"min=0"`
+	LocationID       uint              `json:"location_id"`
+	UnitCost         model.Money       `json:"unit_cost" binding:"min=0"`
+	ReorderPoint     model.Quantity    `json:"reorder_point" binding:"min=0"`
+	ReorderQuantity  model.Quantity    `json:"reorder_quantity" binding:"min=0"`
+	SupplierID       *uint             `json:"supplier_id"`
+	BatchTracked     bool              `json:"batch_tracked"`
+	SerialTracked    bool              `json:"serial_tracked"`
+	Lot              *LotDTO           `json:"lot"`
+	Serials          []string          `json:"serials"`
+	Components       []KitComponentDTO `json:"components" binding:"dive"`
+	UnitDTO
+}
+
+// UpdateProductDTO has no stock, which changes through stock adjustments. A variant keeps
+// its parent's category, tax class and unit.
+type UpdateProductDTO struct {
+	Name             string            `json:"name" binding:"required"`
+	SKU              string            `json:"sku" binding:"max=64"`
+	Barcodes         []BarcodeDTO      `json:"barcodes" binding:"dive"`
+	CategoryID       *uint             `json:"category_id"`
+	Price            model.Money       `json:"price" binding:"required"`
+	PriceIncludesTax bool              `json:"price_includes_tax"`
+	TaxClassID       *uint             `json:"tax_class_id"`
+	ReorderPoint     model.Quantity    `json:"reorder_point" binding:"min=0"`
+	ReorderQuantity  model.Quantity    `json:"reorder_quantity" binding:"min=0"`
+	SupplierID       *uint             `json:"supplier_id"`
+	BatchTracked     bool              `json:"batch_tracked"`
+	SerialTracked    bool              `json:"serial_tracked"`
+	Components       []KitComponentDTO `json:"components" binding:"dive"`
+	UnitDTO
+}
+
+// StockAdjustmentDTO changes stock by Quantity, which is negative to take stock away, at
+// LocationID or the default location. For a batch-tracked product Lot is the lot stock is
+// added to, and is required then; stock taken away comes from Lot, or from the lots
+// expiring first when it is omitted. A serial-tracked product needs Serials, one per unit.
+type StockAdjustmentDTO struct {
+	Quantity   model.Quantity `json:"quantity" binding:"required"`
+	Reason     string         `json:"reason" binding:"required,oneof=damage theft found correction"`
+	LocationID uint           `json:"location_id"`
+	Lot        *LotDTO        `json:"lot"`
+	Serials    []string       `json:"serials"`
+	Note       string         `json:"note"`
+}
+
+// GenerateVariantsDTO lists the options a product varies in, such as size and colour, with
+// their values; a variant is made for each combination.
+type GenerateVariantsDTO struct {
+	Options []ProductOptionDTO `json:"options" binding:"required,min=1,dive"`
+}
+
+type ProductOptionDTO struct {
+	Name   string   `json:"name" binding:"required"`
+	Values []string `json:"values" binding:"required,min=1,dive,required"`
 }
 
 type ProductDTO struct {
-	ID    uint    `json:"id"`
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
-	Stock int     `json:"stock"`
+	ID    uint           `json:"id"`
+	Name  string         `json:"name"`
+	Price model.Money    `json:"price"`
+	Stock model.Quantity `json:"stock"`
 }
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+// CreatePromotionDTO only needs the fields its type uses: buy_quantity and get_quantity for
+// buy_x_get_y, bundle_quantity and bundle_price for bundle, min_spend with percent or amount
+// for spend_threshold, and percent for sale.
+type CreatePromotionDTO struct {
+	Name           string        `json:"name" binding:"required"`
+	Type           string        `json:"type" binding:"required,oneof=buy_x_get_y bundle spend_threshold sale"`
+	Active         bool          `json:"active"`
+	Priority       int           `json:"priority"`
+	StartsAt       *time.Time    `json:"starts_at"`
+	EndsAt         *time.Time    `json:"ends_at"`
+	DailyFrom      string        `json:"daily_from" binding:"omitempty,datetime=15:04"`
+	DailyTo        string        `json:"daily_to" binding:"omitempty,datetime=15:04"`
+	BuyQuantity    int           `json:"buy_quantity" binding:"min=0"`
+	GetQuantity    int           `json:"get_quantity" binding:"min=0"`
+	BundleQuantity int           `json:"bundle_quantity" binding:"min=0"`
+	BundlePrice    model.Money   `json:"bundle_price" binding:"min=0"`
+	MinSpend       model.Money   `json:"min_spend" binding:"min=0"`
+	Percent        model.Percent `json:"percent" binding:"min=0,max=1000000"`
+	Amount         model.Money   `json:"amount" binding:"min=0"`
+	CategoryID     *uint         `json:"category_id"`
+	ProductIDs     []uint        `json:"product_ids"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type CreateSupplierDTO struct {
+	Name    string `json:"name" binding:"required"`
+	Email   string `json:"email"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+}
+
+// PurchaseOrderItemDTO.Quantity and UnitCost are in the product's purchase unit.
+type PurchaseOrderItemDTO struct {
+	ProductID uint           `json:"product_id" binding:"required"`
+	Quantity  model.Quantity `json:"quantity" binding:"required,gt=0"`
+	UnitCost  model.Money    `json:"unit_cost" binding:"min=0"`
+}
+
+// CreatePurchaseOrderDTO.LocationID is where the goods are delivered; the default
+// location when omitted.
+type CreatePurchaseOrderDTO struct {
+	SupplierID uint                   `json:"supplier_id" binding:"required"`
+	LocationID uint                   `json:"location_id"`
+	Reference  string                 `json:"reference"`
+	Notes      string                 `json:"notes"`
+	Items      []PurchaseOrderItemDTO `json:"items" binding:"required,min=1,dive"`
+}
+
+// ReceiveItemDTO.Lot is required for batch-tracked products and Serials, one per unit,
+// for serial-tracked ones.
+type ReceiveItemDTO struct {
+	PurchaseOrderItemID uint           `json:"purchase_order_item_id" binding:"required"`
+	Quantity            model.Quantity `json:"quantity" binding:"required,gt=0"`
+	Lot                 *LotDTO        `json:"lot"`
+	Serials             []string       `json:"serials"`
+}
+
+type ReceivePurchaseOrderDTO struct {
+	Items           []ReceiveItemDTO `json:"items" binding:"required,min=1,dive"`
+	AdditionalCosts model.Money      `json:"additional_costs" binding:"min=0"`
+	Note            string           `json:"note"`
+}
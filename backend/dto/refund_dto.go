@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/nawodahansani/pos-backend/model"
+
+// RefundItemDTO.Serials are the serials returned, one per unit, for a serial-tracked product.
+type RefundItemDTO struct {
+	OrderItemID uint           `json:"order_item_id" binding:"required"`
+	Quantity    model.Quantity `json:"quantity" binding:"required,gt=0"`
+	Serials     []string       `json:"serials"`
+}
+
+type CreateRefundDTO struct {
+	Reason string          `json:"reason" binding:"required"`
+	Items  []RefundItemDTO `json:"items" binding:"required,min=1,dive"`
+}
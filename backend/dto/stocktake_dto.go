@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/nawodahansani/pos-backend/model"
+
+// CreateStocktakeDTO opens a count of LocationID (the default location when omitted),
+// limited to one category when CategoryID is set.
+type CreateStocktakeDTO struct {
+	LocationID uint   `json:"location_id"`
+	CategoryID *uint  `json:"category_id"`
+	Note       string `json:"note"`
+}
+
+// StocktakeCountItemDTO.Quantity is added to what was counted before; zero records that
+// none were found and a negative quantity corrects a miscount.
+type StocktakeCountItemDTO struct {
+	ProductID uint           `json:"product_id" binding:"required"`
+	Quantity  model.Quantity `json:"quantity"`
+}
+
+type StocktakeCountDTO struct {
+	Device string                  `json:"device"`
+	Items  []StocktakeCountItemDTO `json:"items" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type CreateTaxRateDTO struct {
+	Name     string        `json:"name" binding:"required"`
+	Rate     model.Percent `json:"rate" binding:"min=0,max=1000000"`
+	Compound bool          `json:"compound"`
+	Priority int           `json:"priority"`
+}
+
+type CreateTaxClassDTO struct {
+	Name    string `json:"name" binding:"required"`
+	RateIDs []uint `json:"rate_ids"`
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/nawodahansani/pos-backend/config"
 	"github.com/nawodahansani/pos-backend/controller"
+	"github.com/nawodahansani/pos-backend/model"
 	"github.com/nawodahansani/pos-backend/repository/impl"
 	"github.com/nawodahansani/pos-backend/service"
 	"github.com/nawodahansani/pos-backend/middleware"
@@ -21,9 +22,14 @@ func main() {
 	
 	db := config.DB
 
-	// Auto migrate all models
-	if err := config.ResetAndMigrateDB(db); err != nil {
-    	log.Fatalf("Migration error: %v", err)
+	// `migrate` subcommand manages the schema and exits without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(db, os.Args[2:]))
+	}
+
+	// Apply pending migrations; never drops existing data
+	if err := config.MigrateUp(db); err != nil {
+		log.Fatalf("Migration error: %v", err)
 	}
 
 	// repositories/impl
@@ -32,19 +38,69 @@ func main() {
 	custRepo := impl.NewCustomerRepoImpl(db)
 	orderRepo := impl.NewOrderRepoImpl(db)
 	userRepo := impl.NewUserRepository(db)
+	refundRepo := impl.NewRefundRepoImpl(db)
+	reportRepo := impl.NewReportRepoImpl(db)
+	taxRepo := impl.NewTaxRepoImpl(db)
+	categoryRepo := impl.NewCategoryRepoImpl(db)
+	promoRepo := impl.NewPromotionRepoImpl(db)
+	couponRepo := impl.NewCouponRepoImpl(db)
+	moveRepo := impl.NewStockMovementRepoImpl(db)
+	supplierRepo := impl.NewSupplierRepoImpl(db)
+	poRepo := impl.NewPurchaseOrderRepoImpl(db)
+	locationRepo := impl.NewLocationRepoImpl(db)
+	levelRepo := impl.NewStockLevelRepoImpl(db)
+	transferRepo := impl.NewStockTransferRepoImpl(db)
+	stocktakeRepo := impl.NewStocktakeRepoImpl(db)
+	lotRepo := impl.NewLotRepoImpl(db)
+	serialRepo := impl.NewSerialRepoImpl(db)
+	modifierRepo := impl.NewModifierRepoImpl(db)
 
 	// services
+	paymentGateway, err := service.NewPaymentGatewayFromEnv()
+	if err != nil {
+		log.Fatalf("Payment gateway: %v", err)
+	}
 	jwtService := service.NewJWTService() // Add JWT service
 	authService := service.NewAuthService(userRepo, jwtService) // Add auth service
-	prodSvc := service.NewProductService(db, prodRepo)
+	prodSvc := service.NewProductService(db, prodRepo, locationRepo)
 	custSvc := service.NewCustomerService(db, custRepo)
-	orderSvc := service.NewOrderService(db, orderRepo, prodRepo, custRepo)
+	orderSvc := service.NewOrderService(db, orderRepo, prodRepo, custRepo, refundRepo, taxRepo, promoRepo, couponRepo, userRepo, locationRepo, categoryRepo, modifierRepo, paymentGateway)
+	reportSvc := service.NewReportService(reportRepo, prodRepo, categoryRepo)
+	taxSvc := service.NewTaxService(db, taxRepo)
+	categorySvc := service.NewCategoryService(categoryRepo, prodRepo, taxRepo)
+	promoSvc := service.NewPromotionService(promoRepo, prodRepo, categoryRepo)
+	couponSvc := service.NewCouponService(db, couponRepo)
+	inventorySvc := service.NewInventoryService(db, prodRepo, moveRepo, levelRepo, locationRepo, lotRepo)
+	supplierSvc := service.NewSupplierService(supplierRepo)
+	serialSvc := service.NewSerialService(serialRepo, prodRepo)
+	modifierSvc := service.NewModifierService(modifierRepo, prodRepo)
+	locationSvc := service.NewLocationService(db, locationRepo, levelRepo)
+	transferSvc := service.NewStockTransferService(db, transferRepo, locationRepo)
+	stocktakeSvc := service.NewStocktakeService(db, stocktakeRepo, prodRepo, moveRepo, locationRepo, categoryRepo)
+	reorderSvc := service.NewReorderService(db, prodRepo, orderRepo, poRepo, locationRepo)
+	poSvc := service.NewPurchaseOrderService(db, poRepo, supplierRepo, prodRepo, locationRepo)
 
 	// controllers
 	authCtrl := controller.NewAuthController(authService) // Add auth controller
 	prodCtrl := controller.NewProductController(prodSvc)
 	custCtrl := controller.NewCustomerController(custSvc)
 	orderCtrl := controller.NewOrderController(orderSvc)
+	reportCtrl := controller.NewReportController(reportSvc)
+	taxCtrl := controller.NewTaxController(taxSvc)
+	categoryCtrl := controller.NewCategoryController(categorySvc)
+	promoCtrl := controller.NewPromotionController(promoSvc)
+	couponCtrl := controller.NewCouponController(couponSvc)
+	inventoryCtrl := controller.NewInventoryController(inventorySvc, reorderSvc)
+	supplierCtrl := controller.NewSupplierController(supplierSvc)
+	serialCtrl := controller.NewSerialController(serialSvc)
+	modifierCtrl := controller.NewModifierController(modifierSvc)
+	poCtrl := controller.NewPurchaseOrderController(poSvc)
+	locationCtrl := controller.NewLocationController(locationSvc)
+	transferCtrl := controller.NewStockTransferController(transferSvc)
+	stocktakeCtrl := controller.NewStocktakeController(stocktakeSvc)
+
+	// draft purchase orders for low stock are raised in the background
+	go service.RunReorderJob(reorderSvc)
 
 	r := gin.Default()
 
@@ -74,9 +130,86 @@ func main() {
 		// Product routes
 		protected.GET("/products", prodCtrl.List)
 		protected.GET("/products/:id", prodCtrl.GetByID)
+		protected.GET("/products/barcode/:code", prodCtrl.GetByBarcode)
 		protected.POST("/products", prodCtrl.CreateProduct)
 		protected.PUT("/products/:id", prodCtrl.UpdateProduct)
 		protected.DELETE("/products/:id", prodCtrl.DeleteProduct)
+		protected.GET("/products/:id/variants", prodCtrl.ListVariants)
+		protected.POST("/products/:id/variants", prodCtrl.GenerateVariants)
+		protected.GET("/products/:id/modifier-groups", modifierCtrl.ListByProduct)
+		protected.PUT("/products/:id/modifier-groups", middleware.RequireRole(model.RoleManager, model.RoleAdmin), modifierCtrl.SetProductGroups)
+		protected.GET("/products/:id/stock-movements", inventoryCtrl.ListMovements)
+		protected.GET("/products/:id/stock-levels", inventoryCtrl.ListLevels)
+		protected.GET("/products/:id/lots", inventoryCtrl.ListLots)
+		protected.GET("/products/:id/serials", serialCtrl.ListByProduct)
+		protected.POST("/products/:id/stock-adjustments", middleware.RequireRole(model.RoleManager, model.RoleAdmin), inventoryCtrl.AdjustStock)
+		protected.GET("/products/:id/stock-reconciliation", inventoryCtrl.ReconcileProduct)
+
+		// Inventory routes
+		protected.GET("/inventory/reconciliation", inventoryCtrl.Reconcile)
+		protected.GET("/inventory/low-stock", inventoryCtrl.LowStock)
+		protected.GET("/inventory/lots/expiring", inventoryCtrl.ExpiringLots)
+		protected.POST("/inventory/reorder-suggestions", middleware.RequireRole(model.RoleManager, model.RoleAdmin), inventoryCtrl.SuggestReorders)
+
+		// Location routes (changes are admin only)
+		protected.GET("/locations", locationCtrl.List)
+		protected.GET("/locations/:id", locationCtrl.GetByID)
+		protected.GET("/locations/:id/stock", locationCtrl.ListStock)
+		protected.POST("/locations", middleware.RequireRole(model.RoleAdmin), locationCtrl.Create)
+		protected.PUT("/locations/:id", middleware.RequireRole(model.RoleAdmin), locationCtrl.Update)
+		protected.DELETE("/locations/:id", middleware.RequireRole(model.RoleAdmin), locationCtrl.Delete)
+
+		// Stock transfer routes (changes are for managers)
+		protected.GET("/stock-transfers", transferCtrl.List)
+		protected.GET("/stock-transfers/:id", transferCtrl.GetByID)
+		protected.POST("/stock-transfers", middleware.RequireRole(model.RoleManager, model.RoleAdmin), transferCtrl.Create)
+		protected.POST("/stock-transfers/:id/receive", middleware.RequireRole(model.RoleManager, model.RoleAdmin), transferCtrl.Receive)
+		protected.POST("/stock-transfers/:id/cancel", middleware.RequireRole(model.RoleManager, model.RoleAdmin), transferCtrl.Cancel)
+
+		// Stocktake routes (opening, posting and cancelling are for managers)
+		protected.GET("/stocktakes", stocktakeCtrl.List)
+		protected.GET("/stocktakes/:id", stocktakeCtrl.GetByID)
+		protected.POST("/stocktakes", middleware.RequireRole(model.RoleManager, model.RoleAdmin), stocktakeCtrl.Open)
+		protected.GET("/stocktakes/:id/counts", stocktakeCtrl.ListCounts)
+		protected.POST("/stocktakes/:id/counts", stocktakeCtrl.AddCounts)
+		protected.GET("/stocktakes/:id/variance", stocktakeCtrl.Variance)
+		protected.POST("/stocktakes/:id/post", middleware.RequireRole(model.RoleManager, model.RoleAdmin), stocktakeCtrl.Post)
+		protected.POST("/stocktakes/:id/cancel", middleware.RequireRole(model.RoleManager, model.RoleAdmin), stocktakeCtrl.Cancel)
+
+		// Category routes (changes are admin only)
+		protected.GET("/categories", categoryCtrl.List)
+		protected.GET("/categories/tree", categoryCtrl.Tree)
+		protected.GET("/categories/:id", categoryCtrl.GetByID)
+		protected.GET("/categories/:id/products", categoryCtrl.ListProducts)
+		protected.POST("/categories", middleware.RequireRole(model.RoleAdmin), categoryCtrl.Create)
+		protected.PUT("/categories/:id", middleware.RequireRole(model.RoleAdmin), categoryCtrl.Update)
+		protected.DELETE("/categories/:id", middleware.RequireRole(model.RoleAdmin), categoryCtrl.Delete)
+
+		// Supplier routes (changes are for managers)
+		protected.GET("/suppliers", supplierCtrl.List)
+		protected.GET("/suppliers/:id", supplierCtrl.GetByID)
+		protected.POST("/suppliers", middleware.RequireRole(model.RoleManager, model.RoleAdmin), supplierCtrl.Create)
+		protected.PUT("/suppliers/:id", middleware.RequireRole(model.RoleManager, model.RoleAdmin), supplierCtrl.Update)
+		protected.DELETE("/suppliers/:id", middleware.RequireRole(model.RoleManager, model.RoleAdmin), supplierCtrl.Delete)
+
+		// Modifier routes (changes are for managers)
+		protected.GET("/modifier-groups", modifierCtrl.ListGroups)
+		protected.GET("/modifier-groups/:id", modifierCtrl.GetGroup)
+		protected.POST("/modifier-groups", middleware.RequireRole(model.RoleManager, model.RoleAdmin), modifierCtrl.CreateGroup)
+		protected.PUT("/modifier-groups/:id", middleware.RequireRole(model.RoleManager, model.RoleAdmin), modifierCtrl.UpdateGroup)
+		protected.DELETE("/modifier-groups/:id", middleware.RequireRole(model.RoleManager, model.RoleAdmin), modifierCtrl.DeleteGroup)
+
+		// Serial routes
+		protected.GET("/serials/:number", serialCtrl.History)
+
+		// Purchase order routes (changes are for managers)
+		protected.GET("/purchase-orders", poCtrl.List)
+		protected.GET("/purchase-orders/:id", poCtrl.GetByID)
+		protected.POST("/purchase-orders", middleware.RequireRole(model.RoleManager, model.RoleAdmin), poCtrl.Create)
+		protected.PUT("/purchase-orders/:id", middleware.RequireRole(model.RoleManager, model.RoleAdmin), poCtrl.Update)
+		protected.POST("/purchase-orders/:id/send", middleware.RequireRole(model.RoleManager, model.RoleAdmin), poCtrl.Send)
+		protected.POST("/purchase-orders/:id/cancel", middleware.RequireRole(model.RoleManager, model.RoleAdmin), poCtrl.Cancel)
+		protected.POST("/purchase-orders/:id/receive", middleware.RequireRole(model.RoleManager, model.RoleAdmin), poCtrl.Receive)
 
 		// Customer routes
 		protected.GET("/customers", custCtrl.List)
@@ -89,6 +222,46 @@ func main() {
 		protected.GET("/orders", orderCtrl.ListOrders)
 		protected.GET("/orders/:id", orderCtrl.GetOrder)
 		protected.POST("/orders", orderCtrl.CreateOrder)
+		protected.POST("/orders/park", orderCtrl.ParkOrder)
+		protected.PUT("/orders/:id", orderCtrl.UpdateParkedOrder)
+		protected.POST("/orders/:id/resume", orderCtrl.ResumeOrder)
+		protected.POST("/orders/:id/void", orderCtrl.VoidOrder)
+		protected.GET("/orders/:id/refunds", orderCtrl.ListRefunds)
+		protected.POST("/orders/:id/refunds", orderCtrl.RefundOrder)
+
+		// Report routes
+		protected.GET("/reports/sales", reportCtrl.SalesSummary)
+		protected.GET("/reports/payments", reportCtrl.PaymentsByTender)
+		protected.GET("/reports/tax", reportCtrl.TaxByRate)
+		protected.GET("/reports/promotions", reportCtrl.Promotions)
+		protected.GET("/reports/gross-profit", reportCtrl.GrossProfit)
+		protected.GET("/reports/categories", reportCtrl.SalesByCategory)
+
+		// Tax routes (changes are admin only)
+		protected.GET("/tax-rates", taxCtrl.ListRates)
+		protected.POST("/tax-rates", middleware.RequireRole(model.RoleAdmin), taxCtrl.CreateRate)
+		protected.PUT("/tax-rates/:id", middleware.RequireRole(model.RoleAdmin), taxCtrl.UpdateRate)
+		protected.DELETE("/tax-rates/:id", middleware.RequireRole(model.RoleAdmin), taxCtrl.DeleteRate)
+		protected.GET("/tax-classes", taxCtrl.ListClasses)
+		protected.GET("/tax-classes/:id", taxCtrl.GetClass)
+		protected.POST("/tax-classes", middleware.RequireRole(model.RoleAdmin), taxCtrl.CreateClass)
+		protected.PUT("/tax-classes/:id", middleware.RequireRole(model.RoleAdmin), taxCtrl.UpdateClass)
+		protected.DELETE("/tax-classes/:id", middleware.RequireRole(model.RoleAdmin), taxCtrl.DeleteClass)
+
+		// Promotion routes (changes are admin only)
+		protected.GET("/promotions", promoCtrl.List)
+		protected.GET("/promotions/:id", promoCtrl.GetByID)
+		protected.POST("/promotions", middleware.RequireRole(model.RoleAdmin), promoCtrl.Create)
+		protected.PUT("/promotions/:id", middleware.RequireRole(model.RoleAdmin), promoCtrl.Update)
+		protected.DELETE("/promotions/:id", middleware.RequireRole(model.RoleAdmin), promoCtrl.Delete)
+
+		// Coupon routes (changes are admin only)
+		protected.GET("/coupons", couponCtrl.List)
+		protected.GET("/coupons/:id", couponCtrl.GetByID)
+		protected.POST("/coupons", middleware.RequireRole(model.RoleAdmin), couponCtrl.Create)
+		protected.POST("/coupons/generate", middleware.RequireRole(model.RoleAdmin), couponCtrl.Generate)
+		protected.PUT("/coupons/:id", middleware.RequireRole(model.RoleAdmin), couponCtrl.Update)
+		protected.DELETE("/coupons/:id", middleware.RequireRole(model.RoleAdmin), couponCtrl.Delete)
 	}
 
 	// Health check route
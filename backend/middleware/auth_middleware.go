@@ -65,6 +65,25 @@ func AuthMiddleware(jwtService service.JWTService) gin.HandlerFunc {
 	}
 }
 
+// RequireRole only lets through users whose role is one of roles. It must run after
+// AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString("role")
+		for _, r := range roles {
+			if r == role {
+				ctx.Next()
+				return
+			}
+		}
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Insufficient permissions",
+		})
+		ctx.Abort()
+	}
+}
+
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/nawodahansani/pos-backend/config"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `usage: pos-backend migrate <command>
+
+commands:
+  status          list migrations and whether they are applied
+  up              apply all pending migrations
+  down [n]        roll back the last n migrations (default 1)
+  to <version>    migrate up or down to exactly <version> (0 rolls back everything)
+  reset --force   drop all tables and re-apply every migration (APP_ENV=development only)`
+
+// runMigrateCommand handles `pos-backend migrate ...` and returns the process exit code.
+func runMigrateCommand(db *gorm.DB, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "status":
+		err = printMigrationStatus(db)
+	case "up":
+		err = config.MigrateUp(db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid step count %q\n", args[1])
+				return 2
+			}
+		}
+		err = config.MigrateDown(db, steps)
+	case "to":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil {
+			fmt.Fprintf(os.Stderr, "invalid version %q\n", args[1])
+			return 2
+		}
+		err = config.MigrateTo(db, version)
+	case "reset":
+		// wiping every table needs to be asked for explicitly, even in development
+		if len(args) < 2 || args[1] != "--force" {
+			fmt.Fprintln(os.Stderr, "migrate reset deletes all data; run it as `migrate reset --force`")
+			return 2
+		}
+		err = config.ResetDB(db)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate %s: %v\n", args[0], err)
+		return 1
+	}
+	if args[0] != "status" {
+		return reportSchemaVersion(db)
+	}
+	return 0
+}
+
+func reportSchemaVersion(db *gorm.DB) int {
+	version, err := config.CurrentVersion(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read schema version: %v\n", err)
+		return 1
+	}
+	fmt.Printf("schema is at version %d\n", version)
+	return 0
+}
+
+func printMigrationStatus(db *gorm.DB) error {
+	list, err := config.GetMigrationStatus(db)
+	if err != nil {
+		return err
+	}
+	for _, m := range list {
+		state := "pending"
+		if m.Applied {
+			state = "applied " + m.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d  %-40s %s\n", m.Version, m.Name, state)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package model
+
+// Barcode symbologies. EAN-13 and UPC-A codes carry a check digit; a UPC-A code is also
+// read as the EAN-13 code with a leading zero. A scale barcode is the first seven digits
+// of the EAN-13 labels a scale prints for the product, the rest carrying a weight or price.
+const (
+	BarcodeEAN13   = "ean13"
+	BarcodeUPCA    = "upca"
+	BarcodeCode128 = "code128"
+	BarcodeScale   = "scale"
+)
+
+// Barcode is one of a product's barcodes. Codes are unique across products.
+type Barcode struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ProductID uint   `json:"product_id"`
+	Code      string `json:"code"`
+	Type      string `json:"type"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Costing methods, chosen with COSTING_METHOD. Cost layers are kept and used up oldest
+// first under either method, so the method can be changed; it only decides what stock
+// going out is charged at.
+const (
+	CostingFIFO    = "fifo"
+	CostingAverage = "average"
+)
+
+// ProductProfit is a product's sales, net of tax and refunds, against what the goods cost
+// over a period, not a table.
+type ProductProfit struct {
+	ProductID   uint     `json:"product_id"`
+	Name        string   `json:"name"`
+	Quantity    Quantity `json:"quantity"`
+	NetSales    Money    `json:"net_sales"`
+	CostOfGoods Money    `json:"cost_of_goods"`
+	GrossProfit Money    `json:"gross_profit"`
+}
+
+// CostLayer is stock that came in at one cost. Cost is the value of the whole layer and
+// Remaining the units not used up yet; a part is valued as its share of Cost, so a layer
+// used up in several goes adds up to exactly what it cost.
+type CostLayer struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProductID uint      `json:"product_id"`
+	Quantity  Quantity  `json:"quantity"`
+	Remaining Quantity  `json:"remaining"`
+	Cost      Money     `json:"cost"`
+	RefType   string    `json:"ref_type,omitempty"`
+	RefID     *uint     `json:"ref_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Coupon is a code a customer hands over at checkout for a discount on the whole order.
+// MaxUses 1 makes it single-use and 0 unlimited; PerCustomerLimit 0 means no limit per
+// customer. Type, Percent and Amount work as for a manual Discount. The discount is
+// worked out on what is left after promotions and manual discounts, and only when that
+// is at least MinSpend.
+type Coupon struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	Code             string     `json:"code"`
+	Description      string     `json:"description"`
+	Type             string     `json:"type"`
+	Percent          Percent    `json:"percent"`
+	Amount           Money      `json:"amount"`
+	MinSpend         Money      `json:"min_spend"`
+	MaxUses          int        `json:"max_uses"`
+	UsedCount        int        `json:"used_count"`
+	PerCustomerLimit int        `json:"per_customer_limit"`
+	Active           bool       `json:"active"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// CouponRedemption records a coupon used on a completed order.
+type CouponRedemption struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CouponID   uint      `json:"coupon_id"`
+	OrderID    uint      `json:"order_id"`
+	CustomerID uint      `json:"customer_id"`
+	Amount     Money     `json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+}
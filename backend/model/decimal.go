@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseDecimal parses a decimal string into an integer scaled by 10^places. Digits past
+// the last place are rounded half away from zero.
+func parseDecimal(s string, places int) (int64, error) {
+	s = strings.TrimSpace(s)
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, errors.New("empty number")
+	}
+	for _, part := range []string{whole, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, errors.New("not a decimal number")
+			}
+		}
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	scale := int64(math.Pow10(places))
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/scale-1 {
+		return 0, errors.New("out of range")
+	}
+	v := units * scale
+	if places > 0 {
+		padded := (frac + strings.Repeat("0", places))[:places]
+		f, _ := strconv.ParseInt(padded, 10, 64)
+		v += f
+	}
+	if len(frac) > places && frac[places] >= '5' {
+		v++
+	}
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// formatDecimal is the inverse of parseDecimal, always printing every place.
+func formatDecimal(v int64, places int) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	if places == 0 {
+		return sign + strconv.FormatInt(v, 10)
+	}
+	scale := int64(math.Pow10(places))
+	frac := strconv.FormatInt(v%scale, 10)
+	return sign + strconv.FormatInt(v/scale, 10) + "." + strings.Repeat("0", places-len(frac)) + frac
+}
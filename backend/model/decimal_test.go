@@ -0,0 +1,60 @@
+package model
+
+import "testing"
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		places  int
+		want    int64
+		wantErr bool
+	}{
+		{in: "1.5", places: 3, want: 1500},
+		{in: "0.0005", places: 3, want: 1},
+		{in: "0.0004", places: 3, want: 0},
+		{in: "-0.0005", places: 3, want: -1},
+		{in: "2.5", places: 0, want: 3},
+		{in: "-2.5", places: 0, want: -3},
+		{in: "2.4", places: 0, want: 2},
+		{in: "42", places: 2, want: 4200},
+		{in: "9223372036854775807", places: 2, wantErr: true},
+		{in: "-", places: 2, wantErr: true},
+		{in: "1e3", places: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDecimal(tt.in, tt.places)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseDecimal(%q, %d) = %d, want an error", tt.in, tt.places, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseDecimal(%q, %d) = %d, %v, want %d", tt.in, tt.places, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	tests := []struct {
+		v      int64
+		places int
+		want   string
+	}{
+		{1500, 3, "1.500"},
+		{1, 3, "0.001"},
+		{-1, 3, "-0.001"},
+		{42, 0, "42"},
+		{-42, 0, "-42"},
+		{4200, 2, "42.00"},
+	}
+	for _, tt := range tests {
+		if got := formatDecimal(tt.v, tt.places); got != tt.want {
+			t.Errorf("formatDecimal(%d, %d) = %q, want %q", tt.v, tt.places, got, tt.want)
+		}
+		// every formatted value parses back to itself
+		if back, err := parseDecimal(tt.want, tt.places); err != nil || back != tt.v {
+			t.Errorf("parseDecimal(%q, %d) = %d, %v, want %d", tt.want, tt.places, back, err, tt.v)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+// Discount types.
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// Discount reason codes.
+const (
+	DiscountReasonDamaged    = "damaged"
+	DiscountReasonPriceMatch = "price_match"
+	DiscountReasonLoyalty    = "loyalty"
+	DiscountReasonStaff      = "staff"
+	DiscountReasonOther      = "other"
+)
+
+// Discount is a manual discount given at the till, on one order line or on the whole order.
+// Percent is only set for percentage discounts. Amount is the money taken off, in the same
+// terms as the price (tax-inclusive for tax-inclusive products); tax is charged on what is left.
+type Discount struct {
+	Type    string  `json:"type,omitempty"`
+	Percent Percent `json:"percent"`
+	Amount  Money   `json:"amount"`
+	Reason  string  `json:"reason,omitempty"`
+}
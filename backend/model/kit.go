@@ -0,0 +1,21 @@
+package model
+
+// KitComponent is a line of a kit's bill of materials: every kit is made of Quantity
+// units of the component product.
+type KitComponent struct {
+	ID          uint     `gorm:"primaryKey" json:"id"`
+	KitID       uint     `json:"kit_id"`
+	ComponentID uint     `json:"component_id"`
+	Quantity    Quantity `json:"quantity"`
+	Component   *Product `gorm:"foreignKey:ComponentID" json:"component,omitempty"`
+}
+
+// OrderItemComponent is a component of the kit sold on an order line, Quantity per kit;
+// Cost is for the whole line.
+type OrderItemComponent struct {
+	ID          uint     `gorm:"primaryKey" json:"id"`
+	OrderItemID uint     `json:"order_item_id"`
+	ProductID   uint     `json:"product_id"`
+	Quantity    Quantity `json:"quantity"`
+	Cost        Money    `json:"cost"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// Location types.
+const (
+	LocationStore     = "store"
+	LocationWarehouse = "warehouse"
+)
+
+// Location is a store or warehouse holding stock. Exactly one location is the default; it
+// is used when a sale, receipt or adjustment does not name one.
+type Location struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	IsDefault bool      `json:"is_default"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StockLevel is a product's stock at one location. Product.Stock is the sum of its levels;
+// stock in transit between locations is in neither.
+type StockLevel struct {
+	ProductID  uint      `gorm:"primaryKey" json:"product_id"`
+	LocationID uint      `gorm:"primaryKey" json:"location_id"`
+	Quantity   Quantity  `json:"quantity"`
+	Location   *Location `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+}
+
+// Stock transfer statuses. Stock leaves the source when the transfer is created and
+// arrives at the destination when it is received; a cancelled transfer returns it.
+const (
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+type StockTransfer struct {
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	FromLocationID uint                `json:"from_location_id"`
+	ToLocationID   uint                `json:"to_location_id"`
+	Status         string              `json:"status"`
+	Note           string              `json:"note"`
+	CreatedBy      uint                `json:"created_by"`
+	ReceivedBy     *uint               `json:"received_by,omitempty"`
+	ReceivedAt     *time.Time          `json:"received_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	Items          []StockTransferItem `gorm:"foreignKey:StockTransferID" json:"items"`
+}
+
+type StockTransferItem struct {
+	ID              uint     `gorm:"primaryKey" json:"id"`
+	StockTransferID uint     `json:"stock_transfer_id"`
+	ProductID       uint     `json:"product_id"`
+	Quantity        Quantity `json:"quantity"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Lot is a batch of a batch-tracked product at one location. A batch-tracked product's
+// stock level at a location is the sum of its lots there. ExpiresOn is a date; a lot is
+// expired from the day after it and may no longer be sold. Lots without one never expire.
+type Lot struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ProductID  uint       `json:"product_id"`
+	LocationID uint       `json:"location_id"`
+	Number     string     `json:"number"`
+	ExpiresOn  *time.Time `gorm:"type:date" json:"expires_on"`
+	Quantity   Quantity   `json:"quantity"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Product    *Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	Location   *Location  `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+}
+
+// Expired reports whether the lot is past its expiry date on day now.
+func (l *Lot) Expired(now time.Time) bool {
+	if l.ExpiresOn == nil {
+		return false
+	}
+	y, m, d := now.Date()
+	return l.ExpiresOn.Before(time.Date(y, m, d, 0, 0, 0, 0, l.ExpiresOn.Location()))
+}
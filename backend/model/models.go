@@ -2,6 +2,13 @@ package model
 
 import "time"
 
+// User roles, lowest to highest.
+const (
+	RoleUser    = "user"
+	RoleManager = "manager"
+	RoleAdmin   = "admin"
+)
+
 type User struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	FirstName string    `json:"first_name" gorm:"not null"`
@@ -28,29 +35,249 @@ type Customer struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
 	Phone     string    `json:"phone"`
+	TaxExempt bool      `json:"tax_exempt"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Category is a node of the catalog tree; top-level categories have no ParentID. A category
+// covers the products of its subcategories too, for promotions, stocktakes and reports.
+// TaxClassID is the tax class of its products that have none of their own, inherited by
+// subcategories without one. Children is only filled in when the tree is listed.
+type Category struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Name       string     `json:"name"`
+	ParentID   *uint      `json:"parent_id"`
+	TaxClassID *uint      `json:"tax_class_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Children   []Category `gorm:"-" json:"children,omitempty"`
+}
+
+// Product quantities are in Unit to Precision places. Variants (ParentID set) and kit
+// components hold the stock of products with HasVariants or IsKit.
 type Product struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Stock     int       `json:"stock"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Name             string    `json:"name"`
+	SKU              string    `gorm:"column:sku" json:"sku"`
+	CategoryID       *uint     `json:"category_id"`
+	Price            Money     `json:"price"`
+	PriceIncludesTax bool      `json:"price_includes_tax"`
+	TaxClassID       *uint     `json:"tax_class_id"`
+	Stock            Quantity  `json:"stock"`
+	ReorderPoint     Quantity  `json:"reorder_point"`
+	ReorderQuantity  Quantity  `json:"reorder_quantity"`
+	SupplierID       *uint     `json:"supplier_id"`
+	AverageCost      Money     `json:"average_cost"`
+	BatchTracked     bool      `json:"batch_tracked"`
+	SerialTracked    bool      `json:"serial_tracked"`
+	Unit             string    `json:"unit"`
+	Precision        int       `json:"precision"`
+	PurchaseUnit     string    `json:"purchase_unit"`
+	PackSize         Quantity  `json:"pack_size"`
+	ParentID         *uint     `json:"parent_id"`
+	HasVariants      bool      `json:"has_variants"`
+	PriceOverride    bool      `json:"price_override"`
+	IsKit            bool      `json:"is_kit"`
+	CreatedAt        time.Time `json:"created_at"`
+	// StockLevels breaks Stock down by location; it is only loaded when listing products.
+	StockLevels []StockLevel `gorm:"foreignKey:ProductID" json:"stock_levels,omitempty"`
+	Barcodes    []Barcode    `gorm:"foreignKey:ProductID" json:"barcodes"`
+	// Options and Variants are loaded for a product with variants, OptionValues for a
+	// variant.
+	Options      []ProductOption      `gorm:"foreignKey:ProductID" json:"options,omitempty"`
+	Variants     []Product            `gorm:"foreignKey:ParentID" json:"variants,omitempty"`
+	OptionValues []ProductOptionValue `gorm:"many2many:variant_option_values;joinForeignKey:ProductID;joinReferences:OptionValueID" json:"option_values,omitempty"`
+	// ModifierGroups are the modifier groups offered when the product is sold.
+	ModifierGroups []ModifierGroup `gorm:"many2many:product_modifier_groups" json:"modifier_groups,omitempty"`
+	// Components is a kit's bill of materials.
+	Components []KitComponent `gorm:"foreignKey:KitID" json:"components,omitempty"`
 }
 
+// Order statuses. Parked orders are carts saved for later: they hold no stock
+// and do not count as sales until resumed and completed.
+const (
+	OrderStatusParked            = "parked"
+	OrderStatusCompleted         = "completed"
+	OrderStatusVoided            = "voided"
+	OrderStatusPartiallyRefunded = "partially_refunded"
+	OrderStatusRefunded          = "refunded"
+)
+
+// Order.Discount is the manual order discount, shared out over the lines. A parked order
+// keeps its CouponCode, but the coupon is only redeemed when the order completes.
 type Order struct {
-	ID         uint        `gorm:"primaryKey" json:"id"`
-	CustomerID uint        `json:"customer_id"`
-	Total      float64     `json:"total"`
-	CreatedAt  time.Time   `json:"created_at"`
-	Items      []OrderItem `gorm:"foreignKey:OrderID" json:"items"`
+	ID                 uint           `gorm:"primaryKey" json:"id"`
+	CustomerID         uint           `json:"customer_id"`
+	LocationID         uint           `json:"location_id"`
+	Status             string         `json:"status"`
+	Discount           Discount       `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
+	DiscountTotal      Money          `json:"discount_total"`
+	DiscountApprovedBy *uint          `json:"discount_approved_by,omitempty"`
+	PromotionTotal     Money          `json:"promotion_total"`
+	CouponID           *uint          `json:"coupon_id,omitempty"`
+	CouponCode         string         `json:"coupon_code,omitempty"`
+	CouponDiscount     Money          `json:"coupon_discount"`
+	Subtotal           Money          `json:"subtotal"`
+	TaxTotal           Money          `json:"tax_total"`
+	Total              Money          `json:"total"`
+	RefundedTotal      Money          `json:"refunded_total"`
+	VoidReason         string         `json:"void_reason,omitempty"`
+	VoidedBy           *uint          `json:"voided_by,omitempty"`
+	VoidedAt           *time.Time     `json:"voided_at,omitempty"`
+	PaidTotal          Money          `json:"paid_total"`
+	ChangeDue          Money          `json:"change_due"`
+	CompletedAt        *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	Items              []OrderItem    `gorm:"foreignKey:OrderID" json:"items"`
+	Payments           []Payment      `gorm:"foreignKey:OrderID" json:"payments"`
+	Refunds            []Refund       `gorm:"foreignKey:OrderID" json:"refunds"`
+	RefundTenders      []RefundTender `gorm:"foreignKey:OrderID" json:"refund_tenders"`
 }
 
+// OrderItem.Price is the unit price with its Modifiers' deltas; every discount comes off
+// before tax. Cost is the cost of goods sold, not counting modifier ingredients.
 type OrderItem struct {
-	ID        uint    `gorm:"primaryKey" json:"id"`
-	OrderID   uint    `json:"order_id"`
-	ProductID uint    `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ID                uint                 `gorm:"primaryKey" json:"id"`
+	OrderID           uint                 `json:"order_id"`
+	ProductID         uint                 `json:"product_id"`
+	Quantity          Quantity             `json:"quantity"`
+	RefundedQuantity  Quantity             `json:"refunded_quantity"`
+	Price             Money                `json:"price"`
+	PriceIncludesTax  bool                 `json:"price_includes_tax"`
+	PromotionDiscount Money                `json:"promotion_discount"`
+	Promotions        []OrderItemPromotion `gorm:"foreignKey:OrderItemID" json:"promotions"`
+	Discount          Discount             `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
+	OrderDiscount     Money                `json:"order_discount"`
+	CouponDiscount    Money                `json:"coupon_discount"`
+	NetAmount         Money                `json:"net_amount"`
+	TaxAmount         Money                `json:"tax_amount"`
+	Total             Money                `json:"total"`
+	Cost              Money                `json:"cost"`
+	Taxes             []OrderItemTax       `gorm:"foreignKey:OrderItemID" json:"taxes"`
+	Serials           []OrderItemSerial    `gorm:"foreignKey:OrderItemID" json:"serials,omitempty"`
+	Modifiers         []OrderItemModifier  `gorm:"foreignKey:OrderItemID" json:"modifiers,omitempty"`
+	Components        []OrderItemComponent `gorm:"foreignKey:OrderItemID" json:"components,omitempty"`
+}
+
+// Tender types accepted as payment.
+const (
+	TenderCash        = "cash"
+	TenderCard        = "card"
+	TenderStoreCredit = "store_credit"
+	TenderOther       = "other"
+)
+
+// Payment statuses. A card payment is pending until it is captured, after its order is saved.
+const (
+	PaymentPending   = "pending"
+	PaymentCompleted = "completed"
+	PaymentFailed    = "failed"
+)
+
+// Payment is one tender applied to an order; an order may be split across several.
+// Amount is what was applied to the order. For cash, Tendered is what the customer
+// handed over and Change what was given back. Card payments carry the gateway transaction,
+// and Failure is the gateway's error when Status is failed.
+type Payment struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrderID        uint      `json:"order_id"`
+	Tender         string    `json:"tender"`
+	Amount         Money     `json:"amount"`
+	Tendered       Money     `json:"tendered"`
+	Change         Money     `json:"change"`
+	RefundedAmount Money     `json:"refunded_amount"`
+	Reference      string    `json:"reference,omitempty"`
+	GatewayTxnID   string    `json:"gateway_txn_id,omitempty"`
+	Status         string    `json:"status"`
+	Failure        string    `json:"failure,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Refund is the document recorded when goods from an order are returned.
+type Refund struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	OrderID   uint           `json:"order_id"`
+	UserID    uint           `json:"user_id"`
+	Reason    string         `json:"reason"`
+	TaxTotal  Money          `json:"tax_total"`
+	Total     Money          `json:"total"`
+	CreatedAt time.Time      `json:"created_at"`
+	Items     []RefundItem   `gorm:"foreignKey:RefundID" json:"items"`
+	Tenders   []RefundTender `gorm:"foreignKey:RefundID" json:"tenders"`
+}
+
+// RefundItem.Amount is the tax-inclusive amount given back; TaxAmount is the tax part of it.
+type RefundItem struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	RefundID    uint            `json:"refund_id"`
+	OrderItemID uint            `json:"order_item_id"`
+	ProductID   uint            `json:"product_id"`
+	Quantity    Quantity        `json:"quantity"`
+	TaxAmount   Money           `json:"tax_amount"`
+	Amount      Money           `json:"amount"`
+	Cost        Money           `json:"cost"`
+	Taxes       []RefundItemTax `gorm:"foreignKey:RefundItemID" json:"taxes"`
+}
+
+// Statuses of a refund tender. A card tender is pending until the gateway has been asked
+// to refund it, which happens only once the refund or void is saved; a failed one is left
+// for manual follow-up.
+const (
+	RefundTenderPending   = "pending"
+	RefundTenderCompleted = "completed"
+	RefundTenderFailed    = "failed"
+)
+
+// RefundTender records how money of an order was returned: for a refund, or with no
+// RefundID when a completed order was voided. Failure is the gateway's error when Status is
+// failed.
+type RefundTender struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	OrderID      uint   `json:"order_id"`
+	RefundID     *uint  `json:"refund_id,omitempty"`
+	PaymentID    *uint  `json:"payment_id,omitempty"`
+	Tender       string `json:"tender"`
+	Amount       Money  `json:"amount"`
+	GatewayTxnID string `json:"gateway_txn_id,omitempty"`
+	Status       string `json:"status"`
+	Failure      string `json:"failure,omitempty"`
+}
+
+// SalesSummary is an aggregate over orders and refunds, not a table. Sales and refund
+// amounts include tax; the tax figures show how much of them is tax. GrossProfit is net
+// sales less tax and the cost of the goods sold, net of goods refunded.
+type SalesSummary struct {
+	From        *time.Time `json:"from,omitempty"`
+	To          *time.Time `json:"to,omitempty"`
+	OrderCount  int64      `json:"order_count"`
+	Discounts   Money      `json:"discounts"`
+	Promotions  Money      `json:"promotions"`
+	Coupons     Money      `json:"coupons"`
+	GrossSales  Money      `json:"gross_sales"`
+	Refunds     Money      `json:"refunds"`
+	NetSales    Money      `json:"net_sales"`
+	TaxSales    Money      `json:"tax_sales"`
+	TaxRefunded Money      `json:"tax_refunded"`
+	NetTax      Money      `json:"net_tax"`
+	CostOfGoods Money      `json:"cost_of_goods"`
+	GrossProfit Money      `json:"gross_profit"`
+}
+
+// CategorySales is what a category sold over a period, its subcategories included, not a
+// table. Products without a category are totalled under a nil CategoryID.
+type CategorySales struct {
+	CategoryID  *uint    `json:"category_id"`
+	Name        string   `json:"name"`
+	ParentID    *uint    `json:"parent_id,omitempty"`
+	Quantity    Quantity `json:"quantity"`
+	NetSales    Money    `json:"net_sales"`
+	CostOfGoods Money    `json:"cost_of_goods"`
+	GrossProfit Money    `json:"gross_profit"`
+}
+
+// TenderTotal is the amount taken per tender type over a period, not a table.
+type TenderTotal struct {
+	Tender string `json:"tender"`
+	Count  int64  `json:"count"`
+	Amount Money  `json:"amount"`
 }
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ModifierGroup is a choice offered on the products it is attached to, such as milk type
+// or extra shots. At least MinSelect and at most MaxSelect of its modifiers are picked per
+// line; a MaxSelect of 0 sets no upper limit.
+type ModifierGroup struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Name      string     `json:"name"`
+	MinSelect int        `json:"min_select"`
+	MaxSelect int        `json:"max_select"`
+	Modifiers []Modifier `gorm:"foreignKey:GroupID" json:"modifiers"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Modifier changes the unit price of the line it is picked on by PriceDelta. Each unit
+// made with it uses IngredientQuantity of the IngredientID product from stock, when set.
+type Modifier struct {
+	ID                 uint     `gorm:"primaryKey" json:"id"`
+	GroupID            uint     `json:"group_id"`
+	Name               string   `json:"name"`
+	PriceDelta         Money    `json:"price_delta"`
+	IngredientID       *uint    `json:"ingredient_id"`
+	IngredientQuantity Quantity `json:"ingredient_quantity"`
+}
+
+// OrderItemModifier is a modifier picked on an order line, as it was when the line was
+// priced. Cost is what the ingredients it used cost.
+type OrderItemModifier struct {
+	ID                 uint     `gorm:"primaryKey" json:"id"`
+	OrderItemID        uint     `json:"order_item_id"`
+	ModifierID         uint     `json:"modifier_id"`
+	Name               string   `json:"name"`
+	PriceDelta         Money    `json:"price_delta"`
+	IngredientID       *uint    `json:"ingredient_id"`
+	IngredientQuantity Quantity `json:"ingredient_quantity"`
+	Cost               Money    `json:"cost"`
+}
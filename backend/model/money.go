@@ -0,0 +1,119 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Money is an exact amount in minor units (cents). It is stored in NUMERIC(12,2)
+// columns and serialised to JSON as a decimal number with two places, e.g. 12.50.
+//
+// Rounding rules: amounts are only rounded when a fraction of a cent is produced
+// (parsing more than two decimals, applying a percentage or rate, splitting an amount).
+// Such results are rounded half away from zero to the nearest cent. Multiplying by a
+// whole quantity and adding or subtracting amounts is always exact.
+type Money int64
+
+// ParseMoney parses a decimal string such as "12.5", "-3.999" or "7" without going
+// through float64.
+func ParseMoney(s string) (Money, error) {
+	v, err := parseDecimal(s, 2)
+	if err != nil {
+		return 0, fmt.Errorf("invalid money amount %q: %w", s, err)
+	}
+	return Money(v), nil
+}
+
+// MoneyFromFloat converts a float, rounding half away from zero to the nearest cent.
+// Only use it at boundaries where a float is unavoidable.
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * 100))
+}
+
+func (m Money) String() string {
+	return formatDecimal(int64(m), 2)
+}
+
+// Mul multiplies by a quantity, rounding half away from zero to the nearest cent; by a
+// whole quantity the result is exact.
+func (m Money) Mul(qty Quantity) Money {
+	return m.MulRate(int64(qty), int64(OneUnit))
+}
+
+// MulRate returns m * num / den rounded half away from zero to the nearest cent.
+func (m Money) MulRate(num, den int64) Money {
+	return Money(divRound(int64(m)*num, den))
+}
+
+// divRound divides rounding half away from zero.
+func divRound(n, d int64) int64 {
+	if d < 0 {
+		n, d = -n, -d
+	}
+	q, r := n/d, n%d
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= d {
+		if n < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts either a JSON number (12.5) or a string ("12.50").
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case string:
+		p, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = p
+		return nil
+	case []byte:
+		p, err := ParseMoney(string(v))
+		if err != nil {
+			return err
+		}
+		*m = p
+		return nil
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	case float64:
+		*m = MoneyFromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Money", src)
+}
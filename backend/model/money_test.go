@@ -0,0 +1,102 @@
+package model
+
+import "testing"
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "12.5", want: 1250},
+		{in: "7", want: 700},
+		{in: "+7", want: 700},
+		{in: ".25", want: 25},
+		{in: " 3.10 ", want: 310},
+		{in: "0.994", want: 99},
+		{in: "0.995", want: 100},
+		{in: "1.005", want: 101},
+		{in: "-1.005", want: -101},
+		{in: "-3.999", want: -400},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1,50", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %d, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{1250, "12.50"},
+		{-123456, "-1234.56"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMulRate(t *testing.T) {
+	tests := []struct {
+		m        Money
+		num, den int64
+		want     Money
+	}{
+		{100, 1, 3, 33},
+		{200, 1, 3, 67},
+		{149, 1, 100, 1},
+		{150, 1, 100, 2},
+		{-150, 1, 100, -2},
+		{50, 1, 100, 1},
+		{-50, 1, 100, -1},
+		{49, 1, 100, 0},
+		{1001, 13, 100, 130},
+		{100, 1, -3, -33},
+		{-100, -1, 3, 33},
+	}
+	for _, tt := range tests {
+		if got := tt.m.MulRate(tt.num, tt.den); got != tt.want {
+			t.Errorf("Money(%d).MulRate(%d, %d) = %d, want %d", int64(tt.m), tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	tests := []struct {
+		m    Money
+		qty  Quantity
+		want Money
+	}{
+		{199, Units(3), 597},
+		{-199, Units(3), -597},
+		{1299, 267, 347},
+		{101, 500, 51},
+		{-101, 500, -51},
+		{100, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Mul(tt.qty); got != tt.want {
+			t.Errorf("Money(%d).Mul(%s) = %d, want %d", int64(tt.m), tt.qty, got, tt.want)
+		}
+	}
+}
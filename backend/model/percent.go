@@ -0,0 +1,80 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Percent is an exact percentage with four decimal places, stored as ten-thousandths of
+// a percent (9.975% is 99750). It is stored in NUMERIC(9,4) columns and serialised to
+// JSON as a decimal number, e.g. 9.975.
+type Percent int64
+
+// OneHundredPercent is 100% expressed in Percent units.
+const OneHundredPercent Percent = 100 * 10000
+
+func ParsePercent(s string) (Percent, error) {
+	v, err := parseDecimal(s, 4)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q: %w", s, err)
+	}
+	return Percent(v), nil
+}
+
+// String prints the percentage without trailing zeros, e.g. "9.975" or "15".
+func (p Percent) String() string {
+	s := formatDecimal(int64(p), 4)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Of returns p percent of m, rounded half away from zero to the cent.
+func (p Percent) Of(m Money) Money {
+	return m.MulRate(int64(p), int64(OneHundredPercent))
+}
+
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	v, err := ParsePercent(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+func (p Percent) Value() (driver.Value, error) {
+	return formatDecimal(int64(p), 4), nil
+}
+
+func (p *Percent) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*p = 0
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*p = Percent(v * 10000)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Percent", src)
+	}
+	v, err := ParsePercent(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// Promotion types.
+const (
+	// PromotionBuyXGetY gives GetQuantity units free for every BuyQuantity bought; the
+	// cheapest units of each group are the free ones.
+	PromotionBuyXGetY = "buy_x_get_y"
+	// PromotionBundle sells any BundleQuantity qualifying units, mixed freely, for BundlePrice.
+	PromotionBundle = "bundle"
+	// PromotionSpendThreshold takes Percent or Amount off an order worth at least MinSpend.
+	PromotionSpendThreshold = "spend_threshold"
+	// PromotionSale takes Percent off every qualifying product.
+	PromotionSale = "sale"
+)
+
+// Promotion is an automatic price rule applied when an order is priced. A product qualifies
+// if it is listed in Products or belongs to Category; with neither set every product does.
+// StartsAt/EndsAt schedule the promotion and DailyFrom/DailyTo ("15:04", local time)
+// restrict it to a time of day, such as a happy hour.
+type Promotion struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Active         bool       `json:"active"`
+	Priority       int        `json:"priority"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	DailyFrom      string     `json:"daily_from,omitempty"`
+	DailyTo        string     `json:"daily_to,omitempty"`
+	BuyQuantity    int        `json:"buy_quantity,omitempty"`
+	GetQuantity    int        `json:"get_quantity,omitempty"`
+	BundleQuantity int        `json:"bundle_quantity,omitempty"`
+	BundlePrice    Money      `json:"bundle_price"`
+	MinSpend       Money      `json:"min_spend"`
+	Percent        Percent    `json:"percent"`
+	Amount         Money      `json:"amount"`
+	CategoryID     *uint      `json:"category_id,omitempty"`
+	Products       []Product  `gorm:"many2many:promotion_products" json:"products"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// OrderItemPromotion records a promotion applied to an order line and what it took off.
+// Name is copied so receipts still read correctly after the promotion changes.
+type OrderItemPromotion struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	OrderItemID uint   `json:"order_item_id"`
+	PromotionID uint   `json:"promotion_id"`
+	Name        string `json:"name"`
+	Amount      Money  `json:"amount"`
+}
+
+// PromotionTotal is what a promotion gave away over a period, not a table.
+type PromotionTotal struct {
+	PromotionID uint   `json:"promotion_id"`
+	Name        string `json:"name"`
+	OrderCount  int64  `json:"order_count"`
+	Amount      Money  `json:"amount"`
+}
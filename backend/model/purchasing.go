@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+type Supplier struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Purchase order statuses. Only drafts can be edited; stock is received against sent orders.
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+// PurchaseOrder.Total is the ordered quantity at unit cost, before any landed costs.
+// Goods are received into LocationID. Suggested drafts were raised by the reorder job,
+// which leaves CreatedBy empty.
+type PurchaseOrder struct {
+	ID         uint                `gorm:"primaryKey" json:"id"`
+	SupplierID uint                `json:"supplier_id"`
+	LocationID uint                `json:"location_id"`
+	Status     string              `json:"status"`
+	Reference  string              `json:"reference"`
+	Notes      string              `json:"notes"`
+	Total      Money               `json:"total"`
+	Suggested  bool                `json:"suggested"`
+	CreatedBy  *uint               `json:"created_by,omitempty"`
+	SentAt     *time.Time          `json:"sent_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+	Supplier   *Supplier           `gorm:"foreignKey:SupplierID" json:"supplier,omitempty"`
+	Items      []PurchaseOrderItem `gorm:"foreignKey:PurchaseOrderID" json:"items"`
+	Receipts   []GoodsReceipt      `gorm:"foreignKey:PurchaseOrderID" json:"receipts"`
+}
+
+// PurchaseOrderItem.Quantity, ReceivedQuantity and UnitCost are in the product's purchase
+// unit, each holding PackSize of its own units as it was when the line was ordered.
+type PurchaseOrderItem struct {
+	ID               uint     `gorm:"primaryKey" json:"id"`
+	PurchaseOrderID  uint     `json:"purchase_order_id"`
+	ProductID        uint     `json:"product_id"`
+	Quantity         Quantity `json:"quantity"`
+	ReceivedQuantity Quantity `json:"received_quantity"`
+	UnitCost         Money    `json:"unit_cost"`
+	PackSize         Quantity `json:"pack_size"`
+}
+
+// GoodsReceipt records a delivery against a purchase order. AdditionalCosts (freight,
+// duty and the like) are shared over the received lines by value to give their landed cost.
+type GoodsReceipt struct {
+	ID              uint               `gorm:"primaryKey" json:"id"`
+	PurchaseOrderID uint               `json:"purchase_order_id"`
+	UserID          uint               `json:"user_id"`
+	AdditionalCosts Money              `json:"additional_costs"`
+	Note            string             `json:"note"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Items           []GoodsReceiptItem `gorm:"foreignKey:GoodsReceiptID" json:"items"`
+}
+
+// GoodsReceiptItem.Quantity and UnitCost are in the purchase unit of the order line and
+// StockQuantity is what came in in the product's own unit. LandedCost is the line at unit
+// cost plus its share of the additional costs; LandedUnitCost is that per unit of the
+// product, rounded to the cent.
+type GoodsReceiptItem struct {
+	ID                  uint     `gorm:"primaryKey" json:"id"`
+	GoodsReceiptID      uint     `json:"goods_receipt_id"`
+	PurchaseOrderItemID uint     `json:"purchase_order_item_id"`
+	ProductID           uint     `json:"product_id"`
+	Quantity            Quantity `json:"quantity"`
+	StockQuantity       Quantity `json:"stock_quantity"`
+	UnitCost            Money    `json:"unit_cost"`
+	LandedCost          Money    `json:"landed_cost"`
+	LandedUnitCost      Money    `json:"landed_unit_cost"`
+	LotID               *uint    `json:"lot_id,omitempty"`
+}
+
+// ReorderSuggestions is the result of a reorder run: the draft purchase orders raised and
+// the products that need reordering but have no supplier to order from.
+type ReorderSuggestions struct {
+	PurchaseOrders []PurchaseOrder `json:"purchase_orders"`
+	NoSupplier     []uint          `json:"no_supplier"`
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Quantity is an exact quantity in a product's unit, stored as thousandths (0.375 kg is
+// 375) and serialised to JSON as a decimal number, e.g. 0.375 or 12.
+type Quantity int64
+
+// QuantityPlaces is the most decimal places a quantity can have.
+const QuantityPlaces = 3
+
+// OneUnit is a quantity of one.
+const OneUnit Quantity = 1000
+
+// Units is a whole number of units as a Quantity.
+func Units(n int) Quantity {
+	return Quantity(n) * OneUnit
+}
+
+func ParseQuantity(s string) (Quantity, error) {
+	v, err := parseDecimal(s, QuantityPlaces)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q: %w", s, err)
+	}
+	return Quantity(v), nil
+}
+
+// String prints the quantity without trailing zeros, e.g. "0.375" or "12".
+func (q Quantity) String() string {
+	s := formatDecimal(int64(q), QuantityPlaces)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Whole reports whether q is a whole number of units.
+func (q Quantity) Whole() bool {
+	return q%OneUnit == 0
+}
+
+// Int returns the whole units in q, dropping any fraction.
+func (q Quantity) Int() int {
+	return int(q / OneUnit)
+}
+
+// HasPrecision reports whether q uses no more than places decimal places.
+func (q Quantity) HasPrecision(places int) bool {
+	if places >= QuantityPlaces {
+		return true
+	}
+	return int64(q)%int64(math.Pow10(QuantityPlaces-places)) == 0
+}
+
+// RoundUp rounds a positive q up to a whole number of steps.
+func (q Quantity) RoundUp(step Quantity) Quantity {
+	return (q + step - 1) / step * step
+}
+
+// Mul returns q × r rounded half away from zero to a thousandth.
+func (q Quantity) Mul(r Quantity) Quantity {
+	return Quantity(divRound(int64(q)*int64(r), int64(OneUnit)))
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON accepts either a JSON number (0.375) or a string ("0.375").
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	v, err := ParseQuantity(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*q = v
+	return nil
+}
+
+func (q Quantity) Value() (driver.Value, error) {
+	return formatDecimal(int64(q), QuantityPlaces), nil
+}
+
+func (q *Quantity) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*q = 0
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*q = Quantity(v) * OneUnit
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Quantity", src)
+	}
+	v, err := ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+	*q = v
+	return nil
+}
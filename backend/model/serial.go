@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Serial statuses. A serial is in stock at a location, in transit between two, sold on an
+// order, or removed by an adjustment or stocktake.
+const (
+	SerialInStock   = "in_stock"
+	SerialInTransit = "in_transit"
+	SerialSold      = "sold"
+	SerialRemoved   = "removed"
+)
+
+// Serial is one unit of a serial-tracked product. A serial-tracked product's stock level at
+// a location is the number of its serials in stock there. LocationID is where the unit was
+// last in stock and OrderID the order it is sold on while it is sold.
+type Serial struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ProductID  uint      `json:"product_id"`
+	Number     string    `json:"number"`
+	Status     string    `json:"status"`
+	LocationID *uint     `json:"location_id"`
+	OrderID    *uint     `json:"order_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SerialMovement links a serial to each stock movement it was part of.
+type SerialMovement struct {
+	SerialID        uint `gorm:"primaryKey"`
+	StockMovementID uint `gorm:"primaryKey"`
+}
+
+// OrderItemSerial is a serial sold on an order line.
+type OrderItemSerial struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	OrderItemID uint   `json:"order_item_id"`
+	Number      string `json:"number"`
+}
+
+// SerialEvent is a stock movement of a serial, with the customer of the order behind it
+// for sales, refunds and voids, not a table.
+type SerialEvent struct {
+	StockMovementID uint      `json:"stock_movement_id"`
+	Type            string    `json:"type"`
+	LocationID      uint      `json:"location_id"`
+	RefType         string    `json:"ref_type,omitempty"`
+	RefID           *uint     `json:"ref_id,omitempty"`
+	OrderID         *uint     `json:"order_id,omitempty"`
+	CustomerID      *uint     `json:"customer_id,omitempty"`
+	CustomerName    string    `json:"customer_name,omitempty"`
+	UserID          *uint     `json:"user_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// SerialHistory is a serial and everything that happened to it, oldest first, not a table.
+type SerialHistory struct {
+	Serial
+	Events []SerialEvent `json:"events"`
+}
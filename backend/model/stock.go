@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Stock movement types.
+const (
+	StockOpening     = "opening"
+	StockSale        = "sale"
+	StockRefund      = "refund"
+	StockVoid        = "void"
+	StockAdjustment  = "adjustment"
+	StockReceipt     = "receipt"
+	StockTransferOut = "transfer_out"
+	StockTransferIn  = "transfer_in"
+	StockStocktake   = "stocktake"
+)
+
+// Stock adjustment reasons. Damage and theft can only take stock away and found can only
+// add it; a correction goes either way.
+const (
+	AdjustmentDamage     = "damage"
+	AdjustmentTheft      = "theft"
+	AdjustmentFound      = "found"
+	AdjustmentCorrection = "correction"
+)
+
+// StockMovement is an entry in the append-only stock ledger. Every change to
+// Product.Stock is written with one, in the same transaction, so the quantities of a
+// product's movements always add up to its stock, and those at a location to its stock
+// level there. RefType and RefID name the document behind the change, e.g. "order" 42;
+// adjustments carry a Reason instead. Cost is the value the movement brought in, or took
+// out when negative; transfers move no value. Movements of a batch-tracked product name
+// the lot they went into or came out of; those of a serial-tracked product carry Serials,
+// one per unit, which are kept in serial_movements.
+type StockMovement struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ProductID  uint      `json:"product_id"`
+	LocationID uint      `json:"location_id"`
+	Type       string    `json:"type"`
+	Quantity   Quantity  `json:"quantity"`
+	RefType    string    `json:"ref_type,omitempty"`
+	RefID      *uint     `json:"ref_id,omitempty"`
+	LotID      *uint     `json:"lot_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Cost       Money     `json:"cost"`
+	UserID     *uint     `json:"user_id,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	Serials    []string  `gorm:"-" json:"serials,omitempty"`
+}
+
+// StockReconciliation compares a product's stock with the sum of its ledger, not a table.
+type StockReconciliation struct {
+	ProductID   uint     `json:"product_id"`
+	Name        string   `json:"name"`
+	Stock       Quantity `json:"stock"`
+	LedgerTotal Quantity `json:"ledger_total"`
+	Difference  Quantity `json:"difference"`
+}
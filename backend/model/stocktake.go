@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Stocktake statuses.
+const (
+	StocktakeOpen      = "open"
+	StocktakePosted    = "posted"
+	StocktakeCancelled = "cancelled"
+)
+
+// Stocktake is a physical count of one location, optionally limited to a category.
+//
+// The shop keeps trading while the count is open, so quantities are compared as of the
+// moment each product was counted. LedgerMark is the last stock movement when the session
+// was opened and SystemQuantity on each line is the stock level at that mark. A line's
+// Expected quantity adds the movements between the session mark and the line's CountMark;
+// the Variance posted is Counted minus Expected. Marks are read with the products' stock
+// levels locked, so no movement at or below a mark can still be uncommitted.
+type Stocktake struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	LocationID uint            `json:"location_id"`
+	CategoryID *uint           `json:"category_id,omitempty"`
+	Status     string          `json:"status"`
+	Note       string          `json:"note"`
+	LedgerMark uint            `json:"ledger_mark"`
+	OpenedBy   uint            `json:"opened_by"`
+	PostedBy   *uint           `json:"posted_by,omitempty"`
+	PostedAt   *time.Time      `json:"posted_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	Lines      []StocktakeLine `gorm:"foreignKey:StocktakeID" json:"lines,omitempty"`
+}
+
+// StocktakeLine.Counted is the sum of every count of the product, so several devices can
+// count different shelves. Expected and Variance are stored when the session is posted.
+type StocktakeLine struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	StocktakeID    uint      `json:"stocktake_id"`
+	ProductID      uint      `json:"product_id"`
+	SystemQuantity Quantity  `json:"system_quantity"`
+	Counted        *Quantity `json:"counted"`
+	CountMark      uint      `json:"count_mark"`
+	Expected       Quantity  `json:"expected"`
+	Variance       Quantity  `json:"variance"`
+}
+
+// StocktakeCount is one count entered on a device; a negative quantity corrects an earlier
+// count.
+type StocktakeCount struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	StocktakeID uint      `json:"stocktake_id"`
+	ProductID   uint      `json:"product_id"`
+	Quantity    Quantity  `json:"quantity"`
+	Device      string    `json:"device"`
+	UserID      uint      `json:"user_id"`
+	LedgerMark  uint      `json:"ledger_mark"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// TaxRate is a single tax, e.g. VAT at 15%.
+// Compound rates are charged on the net amount plus every tax applied before them;
+// they are applied after all simple rates, in Priority order.
+type TaxRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `json:"name"`
+	Rate      Percent   `json:"rate"`
+	Compound  bool      `json:"compound"`
+	Priority  int       `json:"priority"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaxClass groups the rates that apply to a kind of product (standard, reduced, zero...).
+type TaxClass struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `json:"name"`
+	Rates     []TaxRate `gorm:"many2many:tax_class_rates" json:"rates"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrderItemTax is the amount of one tax rate charged on an order line.
+type OrderItemTax struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	OrderItemID uint    `json:"order_item_id"`
+	TaxRateID   uint    `json:"tax_rate_id"`
+	Name        string  `json:"name"`
+	Rate        Percent `json:"rate"`
+	Amount      Money   `json:"amount"`
+}
+
+// RefundItemTax is the share of one tax rate given back on a refund line.
+type RefundItemTax struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	RefundItemID uint    `json:"refund_item_id"`
+	TaxRateID    uint    `json:"tax_rate_id"`
+	Name         string  `json:"name"`
+	Rate         Percent `json:"rate"`
+	Amount       Money   `json:"amount"`
+}
+
+// TaxTotal is tax collected and refunded per rate over a period, not a table.
+type TaxTotal struct {
+	TaxRateID uint    `json:"tax_rate_id"`
+	Name      string  `json:"name"`
+	Rate      Percent `json:"rate"`
+	Collected Money   `json:"collected"`
+	Refunded  Money   `json:"refunded"`
+	Net       Money   `json:"net"`
+}
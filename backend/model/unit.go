@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// Units of measure products are sold and stocked in.
+const (
+	UnitEach     = "each"
+	UnitKilogram = "kg"
+	UnitGram     = "g"
+	UnitLitre    = "l"
+	UnitMetre    = "m"
+)
+
+// Step is the smallest quantity of the product: one in the last decimal place its
+// Precision allows.
+func (p *Product) Step() Quantity {
+	return Quantity(math.Pow10(QuantityPlaces - p.Precision))
+}
+
+// CheckQuantity rejects a quantity with more decimal places than the product's Precision.
+func (p *Product) CheckQuantity(q Quantity) error {
+	if !q.HasPrecision(p.Precision) {
+		return fmt.Errorf("product %d is counted in %s to %d decimal places, not %s", p.ID, p.Unit, p.Precision, q)
+	}
+	return nil
+}
+
+// UnitsPerPack is how many of the product's own units come in one of its purchase units,
+// one when it is bought as it is sold.
+func (p *Product) UnitsPerPack() Quantity {
+	if p.PackSize > 0 {
+		return p.PackSize
+	}
+	return OneUnit
+}
+
+// QuantityFor is how much of the product amount buys at its price, to the nearest Step.
+func (p *Product) QuantityFor(amount Money) Quantity {
+	step := p.Step()
+	return Quantity(divRound(int64(amount)*int64(OneUnit), int64(p.Price)*int64(step))) * step
+}
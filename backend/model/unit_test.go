@@ -0,0 +1,79 @@
+package model
+
+import "testing"
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		precision int
+		want      Quantity
+	}{
+		{0, 1000},
+		{1, 100},
+		{2, 10},
+		{3, 1},
+	}
+	for _, tt := range tests {
+		p := Product{Precision: tt.precision}
+		if got := p.Step(); got != tt.want {
+			t.Errorf("Step() to %d places = %d, want %d", tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestCheckQuantity(t *testing.T) {
+	tests := []struct {
+		precision int
+		qty       Quantity
+		wantErr   bool
+	}{
+		{precision: 0, qty: 2000},
+		{precision: 0, qty: 2500, wantErr: true},
+		{precision: 0, qty: 1, wantErr: true},
+		{precision: 2, qty: 1250},
+		{precision: 2, qty: 1255, wantErr: true},
+		{precision: 3, qty: 1},
+		{precision: 3, qty: 1255},
+		{precision: 0, qty: -3000},
+		{precision: 0, qty: -2500, wantErr: true},
+	}
+	for _, tt := range tests {
+		p := Product{ID: 1, Unit: UnitKilogram, Precision: tt.precision}
+		err := p.CheckQuantity(tt.qty)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckQuantity(%s) to %d places = %v, want error %v", tt.qty, tt.precision, err, tt.wantErr)
+		}
+	}
+}
+
+func TestQuantityFor(t *testing.T) {
+	tests := []struct {
+		price     Money
+		precision int
+		amount    Money
+		want      Quantity
+	}{
+		{price: 1299, precision: 3, amount: 599, want: 461},
+		{price: 1000, precision: 3, amount: 599, want: 599},
+		{price: 300, precision: 0, amount: 900, want: 3000},
+		{price: 300, precision: 0, amount: 1000, want: 3000},
+		{price: 300, precision: 0, amount: 1050, want: 4000},
+		{price: 250, precision: 1, amount: 137, want: 500},
+		{price: 250, precision: 1, amount: 138, want: 600},
+		{price: 250, precision: 1, amount: 0, want: 0},
+	}
+	for _, tt := range tests {
+		p := Product{Price: tt.price, Precision: tt.precision}
+		if got := p.QuantityFor(tt.amount); got != tt.want {
+			t.Errorf("QuantityFor(%s) at %s to %d places = %d, want %d", tt.amount, tt.price, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestUnitsPerPack(t *testing.T) {
+	if got := (&Product{}).UnitsPerPack(); got != OneUnit {
+		t.Errorf("UnitsPerPack() without a pack size = %d, want %d", got, OneUnit)
+	}
+	if got := (&Product{PackSize: Units(12)}).UnitsPerPack(); got != Units(12) {
+		t.Errorf("UnitsPerPack() = %d, want %d", got, Units(12))
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+// ProductOption is a dimension a product varies in, such as size or colour. Each of the
+// product's variants has one of the option's values.
+type ProductOption struct {
+	ID        uint                 `gorm:"primaryKey" json:"id"`
+	ProductID uint                 `json:"product_id"`
+	Name      string               `json:"name"`
+	Position  int                  `json:"position"`
+	Values    []ProductOptionValue `gorm:"foreignKey:OptionID" json:"values"`
+}
+
+type ProductOptionValue struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	OptionID uint   `json:"option_id"`
+	Value    string `json:"value"`
+	Position int    `json:"position"`
+}
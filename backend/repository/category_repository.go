@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type CategoryRepository interface {
+	GetByID(id uint) (*model.Category, error)
+	List() ([]model.Category, error)
+	Create(c *model.Category) error
+	Update(c *model.Category) error
+	CountChildren(id uint) (int64, error)
+	Delete(id uint) error
+}
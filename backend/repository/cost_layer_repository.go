@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type CostLayerRepository interface {
+	Create(l *model.CostLayer) error
+	// ListOpen returns a product's layers with stock left, oldest first, locked for the
+	// rest of the transaction.
+	ListOpen(productID uint) ([]model.CostLayer, error)
+	SetRemaining(id uint, remaining model.Quantity) error
+}
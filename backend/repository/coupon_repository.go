@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+type CouponRepository interface {
+	GetByID(id uint) (*model.Coupon, error)
+	GetByCode(code string) (*model.Coupon, error)
+	List() ([]model.Coupon, error)
+	Create(c *model.Coupon) error
+	Update(c *model.Coupon) error
+	Delete(id uint) error
+
+	// Redeem takes one use of the coupon. It fails if the coupon is inactive, expired at
+	// the given time or used up, and locks the coupon row until the transaction ends.
+	Redeem(couponID uint, at time.Time) error
+	CountRedemptions(couponID, customerID uint) (int64, error)
+	CreateRedemption(r *model.CouponRedemption) error
+	// ReleaseOrder gives back the coupon uses taken by an order.
+	ReleaseOrder(orderID uint) error
+}
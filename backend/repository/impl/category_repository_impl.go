@@ -0,0 +1,51 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+type categoryRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepoImpl(db *gorm.DB) repository.CategoryRepository {
+	return &categoryRepoImpl{db: db}
+}
+
+func (r *categoryRepoImpl) GetByID(id uint) (*model.Category, error) {
+	var c model.Category
+	if err := r.db.First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *categoryRepoImpl) List() ([]model.Category, error) {
+	var list []model.Category
+	if err := r.db.Order("name").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *categoryRepoImpl) Create(c *model.Category) error {
+	return r.db.Create(c).Error
+}
+
+func (r *categoryRepoImpl) Update(c *model.Category) error {
+	return r.db.Save(c).Error
+}
+
+func (r *categoryRepoImpl) CountChildren(id uint) (int64, error) {
+	var n int64
+	if err := r.db.Model(&model.Category{}).Where("parent_id = ?", id).Count(&n).Error; err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *categoryRepoImpl) Delete(id uint) error {
+	return r.db.Delete(&model.Category{}, id).Error
+}
@@ -0,0 +1,36 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type costLayerRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewCostLayerRepoImpl(db *gorm.DB) repository.CostLayerRepository {
+	return &costLayerRepoImpl{db: db}
+}
+
+func (r *costLayerRepoImpl) Create(l *model.CostLayer) error {
+	return r.db.Create(l).Error
+}
+
+func (r *costLayerRepoImpl) ListOpen(productID uint) ([]model.CostLayer, error) {
+	var list []model.CostLayer
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND remaining > 0", productID).
+		Order("id").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *costLayerRepoImpl) SetRemaining(id uint, remaining model.Quantity) error {
+	return r.db.Model(&model.CostLayer{}).Where("id = ?", id).Update("remaining", remaining).Error
+}
@@ -0,0 +1,97 @@
+package impl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+type couponRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewCouponRepoImpl(db *gorm.DB) repository.CouponRepository {
+	return &couponRepoImpl{db: db}
+}
+
+func (r *couponRepoImpl) GetByID(id uint) (*model.Coupon, error) {
+	var c model.Coupon
+	if err := r.db.First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *couponRepoImpl) GetByCode(code string) (*model.Coupon, error) {
+	var c model.Coupon
+	if err := r.db.Where("code = ?", code).First(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *couponRepoImpl) List() ([]model.Coupon, error) {
+	var list []model.Coupon
+	if err := r.db.Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *couponRepoImpl) Create(c *model.Coupon) error {
+	return r.db.Create(c).Error
+}
+
+func (r *couponRepoImpl) Update(c *model.Coupon) error {
+	return r.db.Save(c).Error
+}
+
+func (r *couponRepoImpl) Delete(id uint) error {
+	return r.db.Delete(&model.Coupon{}, id).Error
+}
+
+func (r *couponRepoImpl) Redeem(couponID uint, at time.Time) error {
+	res := r.db.Model(&model.Coupon{}).
+		Where("id = ? AND active = ?", couponID, true).
+		Where("max_uses = 0 OR used_count < max_uses").
+		Where("expires_at IS NULL OR expires_at > ?", at).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("coupon is no longer valid")
+	}
+	return nil
+}
+
+func (r *couponRepoImpl) CountRedemptions(couponID, customerID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&model.CouponRedemption{}).
+		Where("coupon_id = ? AND customer_id = ?", couponID, customerID).
+		Count(&n).Error
+	return n, err
+}
+
+func (r *couponRepoImpl) CreateRedemption(red *model.CouponRedemption) error {
+	return r.db.Create(red).Error
+}
+
+func (r *couponRepoImpl) ReleaseOrder(orderID uint) error {
+	var list []model.CouponRedemption
+	if err := r.db.Where("order_id = ?", orderID).Find(&list).Error; err != nil {
+		return err
+	}
+	for _, red := range list {
+		err := r.db.Model(&model.Coupon{}).
+			Where("id = ? AND used_count > 0", red.CouponID).
+			Update("used_count", gorm.Expr("used_count - 1")).Error
+		if err != nil {
+			return err
+		}
+	}
+	return r.db.Where("order_id = ?", orderID).Delete(&model.CouponRedemption{}).Error
+}
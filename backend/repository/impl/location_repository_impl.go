@@ -0,0 +1,57 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+type locationRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewLocationRepoImpl(db *gorm.DB) repository.LocationRepository {
+	return &locationRepoImpl{db: db}
+}
+
+func (r *locationRepoImpl) GetByID(id uint) (*model.Location, error) {
+	var l model.Location
+	if err := r.db.First(&l, id).Error; err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *locationRepoImpl) GetDefault() (*model.Location, error) {
+	var l model.Location
+	if err := r.db.Where("is_default").First(&l).Error; err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *locationRepoImpl) List() ([]model.Location, error) {
+	var list []model.Location
+	if err := r.db.Order("name").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *locationRepoImpl) Create(l *model.Location) error {
+	return r.db.Create(l).Error
+}
+
+func (r *locationRepoImpl) Update(l *model.Location) error {
+	return r.db.Save(l).Error
+}
+
+func (r *locationRepoImpl) ClearDefault(keepID uint) error {
+	return r.db.Model(&model.Location{}).
+		Where("is_default AND id <> ?", keepID).
+		Update("is_default", false).Error
+}
+
+func (r *locationRepoImpl) Delete(id uint) error {
+	return r.db.Delete(&model.Location{}, id).Error
+}
@@ -0,0 +1,115 @@
+package impl
+
+import (
+	"errors"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type lotRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewLotRepoImpl(db *gorm.DB) repository.LotRepository {
+	return &lotRepoImpl{db: db}
+}
+
+func (r *lotRepoImpl) GetByID(id uint) (*model.Lot, error) {
+	var lot model.Lot
+	if err := r.db.First(&lot, id).Error; err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+func (r *lotRepoImpl) Find(productID, locationID uint, number string) (*model.Lot, error) {
+	var lot model.Lot
+	if err := r.db.Where("product_id = ? AND location_id = ? AND number = ?", productID, locationID, number).First(&lot).Error; err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// FindOrCreate inserts with ON CONFLICT DO NOTHING so two receipts of a new lot at once
+// both end up with the same row.
+func (r *lotRepoImpl) FindOrCreate(lot *model.Lot) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "location_id"}, {Name: "number"}},
+		DoNothing: true,
+	}).Create(lot).Error
+	if err != nil {
+		return err
+	}
+	found, err := r.Find(lot.ProductID, lot.LocationID, lot.Number)
+	if err != nil {
+		return err
+	}
+	*lot = *found
+	return nil
+}
+
+func (r *lotRepoImpl) ListByProduct(productID uint) ([]model.Lot, error) {
+	var list []model.Lot
+	err := r.db.Preload("Location").
+		Where("product_id = ?", productID).
+		Order("location_id, expires_on NULLS LAST, id").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *lotRepoImpl) ListOpen(productID, locationID uint, sellable bool) ([]model.Lot, error) {
+	q := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND location_id = ? AND quantity > 0", productID, locationID)
+	if sellable {
+		q = q.Where("expires_on IS NULL OR expires_on >= CURRENT_DATE")
+	}
+	var list []model.Lot
+	if err := q.Order("expires_on NULLS LAST, id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *lotRepoImpl) LastExpiring(ids []uint) (*model.Lot, error) {
+	var lot model.Lot
+	if err := r.db.Where("id IN ?", ids).Order("expires_on DESC NULLS FIRST, id DESC").First(&lot).Error; err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+func (r *lotRepoImpl) ListExpiring(days int, locationID uint) ([]model.Lot, error) {
+	q := r.db.Preload("Product").Preload("Location").
+		Where("quantity > 0 AND expires_on < CURRENT_DATE + ?::int", days+1)
+	if locationID != 0 {
+		q = q.Where("location_id = ?", locationID)
+	}
+	var list []model.Lot
+	if err := q.Order("expires_on, product_id, id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *lotRepoImpl) Reduce(id uint, qty model.Quantity) error {
+	res := r.db.Model(&model.Lot{}).
+		Where("id = ? AND quantity >= ?", id, qty).
+		Update("quantity", gorm.Expr("quantity - ?", qty))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("insufficient stock in lot")
+	}
+	return nil
+}
+
+func (r *lotRepoImpl) Increase(id uint, qty model.Quantity) error {
+	return r.db.Model(&model.Lot{}).Where("id = ?", id).Update("quantity", gorm.Expr("quantity + ?", qty)).Error
+}
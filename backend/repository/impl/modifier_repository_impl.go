@@ -0,0 +1,86 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type modifierRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewModifierRepoImpl(db *gorm.DB) repository.ModifierRepository {
+	return &modifierRepoImpl{db: db}
+}
+
+func orderModifiers(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+func (r *modifierRepoImpl) GetGroup(id uint) (*model.ModifierGroup, error) {
+	var g model.ModifierGroup
+	if err := r.db.Preload("Modifiers", orderModifiers).First(&g, id).Error; err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *modifierRepoImpl) GetGroups(ids []uint) ([]model.ModifierGroup, error) {
+	var list []model.ModifierGroup
+	if len(ids) == 0 {
+		return list, nil
+	}
+	if err := r.db.Preload("Modifiers", orderModifiers).Where("id IN ?", ids).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *modifierRepoImpl) ListGroups() ([]model.ModifierGroup, error) {
+	var list []model.ModifierGroup
+	if err := r.db.Preload("Modifiers", orderModifiers).Order("name").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *modifierRepoImpl) ListByProduct(productID uint) ([]model.ModifierGroup, error) {
+	var list []model.ModifierGroup
+	err := r.db.Preload("Modifiers", orderModifiers).
+		Joins("JOIN product_modifier_groups ON product_modifier_groups.modifier_group_id = modifier_groups.id").
+		Where("product_modifier_groups.product_id = ?", productID).
+		Order("modifier_groups.id").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *modifierRepoImpl) CreateGroup(g *model.ModifierGroup) error {
+	return r.db.Create(g).Error
+}
+
+func (r *modifierRepoImpl) UpdateGroup(g *model.ModifierGroup) error {
+	if err := r.db.Omit(clause.Associations).Save(g).Error; err != nil {
+		return err
+	}
+	keep := []uint{0}
+	for i := range g.Modifiers {
+		g.Modifiers[i].GroupID = g.ID
+		if err := r.db.Save(&g.Modifiers[i]).Error; err != nil {
+			return err
+		}
+		keep = append(keep, g.Modifiers[i].ID)
+	}
+	return r.db.Where("group_id = ? AND id NOT IN ?", g.ID, keep).Delete(&model.Modifier{}).Error
+}
+
+func (r *modifierRepoImpl) DeleteGroup(id uint) error {
+	return r.db.Delete(&model.ModifierGroup{}, id).Error
+}
+
+func (r *modifierRepoImpl) SetProductGroups(productID uint, groups []model.ModifierGroup) error {
+	return r.db.Model(&model.Product{ID: productID}).Association("ModifierGroups").Replace(groups)
+}
@@ -1,9 +1,14 @@
 package impl
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/nawodahansani/pos-backend/model"
 	"github.com/nawodahansani/pos-backend/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type orderRepoImpl struct {
@@ -20,17 +25,138 @@ func (r *orderRepoImpl) CreateOrder(order *model.Order) error {
 
 func (r *orderRepoImpl) GetByID(id uint) (*model.Order, error) {
 	var o model.Order
-	if err := r.db.Preload("Items").First(&o, id).Error; err != nil {
+	if err := r.db.Preload("Items.Taxes").Preload("Items.Promotions").Preload("Items.Serials").Preload("Items.Modifiers").Preload("Items.Components").Preload("Payments").Preload("Refunds.Items.Taxes").Preload("Refunds.Tenders").Preload("RefundTenders").First(&o, id).Error; err != nil {
 		return nil, err
 	}
 	return &o, nil
 }
 
-func (r *orderRepoImpl) List() ([]model.Order, error) {
+func (r *orderRepoImpl) List(status string) ([]model.Order, error) {
 	var list []model.Order
-	if err := r.db.Preload("Items").Find(&list).Error; err != nil {
+	q := r.db.Preload("Items.Taxes").Preload("Items.Promotions").Preload("Items.Serials").Preload("Items.Modifiers").Preload("Items.Components").Preload("Payments")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Find(&list).Error; err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
+// Update saves the order's own columns; items and refunds are left untouched.
+func (r *orderRepoImpl) Update(order *model.Order) error {
+	return r.db.Omit(clause.Associations).Save(order).Error
+}
+
+func (r *orderRepoImpl) ReplaceItems(orderID uint, items []model.OrderItem) error {
+	if err := r.db.Where("order_id = ?", orderID).Delete(&model.OrderItem{}).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	for i := range items {
+		items[i].ID = 0
+		items[i].OrderID = orderID
+	}
+	return r.db.Create(&items).Error
+}
+
+func (r *orderRepoImpl) CreatePayments(orderID uint, payments []model.Payment) error {
+	if len(payments) == 0 {
+		return nil
+	}
+	for i := range payments {
+		payments[i].OrderID = orderID
+	}
+	return r.db.Create(&payments).Error
+}
+
+// AddPaymentRefund only succeeds while the refunded amount stays within the payment amount.
+func (r *orderRepoImpl) AddPaymentRefund(paymentID uint, amount model.Money) error {
+	res := r.db.Model(&model.Payment{}).
+		Where("id = ? AND refunded_amount + ? <= amount", paymentID, amount).
+		Update("refunded_amount", gorm.Expr("refunded_amount + ?", amount))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("refund exceeds payment amount")
+	}
+	return nil
+}
+
+func (r *orderRepoImpl) SetPaymentStatus(paymentID uint, status, failure string) error {
+	return r.db.Model(&model.Payment{}).Where("id = ?", paymentID).
+		Updates(map[string]interface{}{"status": status, "failure": failure}).Error
+}
+
+// UpdateStatus moves the order to status "to" only if it is currently in one of "from".
+// The conditional update also row-locks the order for the rest of the transaction.
+func (r *orderRepoImpl) UpdateStatus(orderID uint, from []string, to string) error {
+	res := r.db.Model(&model.Order{}).
+		Where("id = ? AND status IN ?", orderID, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("order %d cannot move to status %s", orderID, to)
+	}
+	return nil
+}
+
+// AddRefundedQuantity only succeeds while the refunded quantity stays within the quantity sold,
+// so concurrent refunds of the same line cannot over-refund it.
+func (r *orderRepoImpl) AddRefundedQuantity(orderItemID uint, qty model.Quantity) error {
+	res := r.db.Model(&model.OrderItem{}).
+		Where("id = ? AND refunded_quantity + ? <= quantity", orderItemID, qty).
+		Update("refunded_quantity", gorm.Expr("refunded_quantity + ?", qty))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("refund quantity exceeds quantity sold")
+	}
+	return nil
+}
+
+func (r *orderRepoImpl) SetItemCost(orderItemID uint, cost model.Money) error {
+	return r.db.Model(&model.OrderItem{}).Where("id = ?", orderItemID).Update("cost", cost).Error
+}
+
+func (r *orderRepoImpl) SetModifierCost(orderItemModifierID uint, cost model.Money) error {
+	return r.db.Model(&model.OrderItemModifier{}).Where("id = ?", orderItemModifierID).Update("cost", cost).Error
+}
+
+func (r *orderRepoImpl) SetComponentCost(orderItemComponentID uint, cost model.Money) error {
+	return r.db.Model(&model.OrderItemComponent{}).Where("id = ?", orderItemComponentID).Update("cost", cost).Error
+}
+
+func (r *orderRepoImpl) AddRefundedTotal(orderID uint, amount model.Money) error {
+	return r.db.Model(&model.Order{}).
+		Where("id = ?", orderID).
+		Update("refunded_total", gorm.Expr("refunded_total + ?", amount)).Error
+}
+
+func (r *orderRepoImpl) UnitsSold(since time.Time) (map[uint]model.Quantity, error) {
+	var rows []struct {
+		ProductID uint
+		Units     model.Quantity
+	}
+	err := r.db.Table("order_items").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Select("order_items.product_id, SUM(order_items.quantity - order_items.refunded_quantity) AS units").
+		Where("orders.status IN ? AND orders.completed_at >= ?",
+			[]string{model.OrderStatusCompleted, model.OrderStatusPartiallyRefunded, model.OrderStatusRefunded}, since).
+		Group("order_items.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	units := make(map[uint]model.Quantity, len(rows))
+	for _, row := range rows {
+		units[row.ProductID] = row.Units
+	}
+	return units, nil
+}
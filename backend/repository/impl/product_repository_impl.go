@@ -6,6 +6,7 @@ import (
 	"github.com/nawodahansani/pos-backend/model"
 	"github.com/nawodahansani/pos-backend/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepoImpl struct {
@@ -18,29 +19,195 @@ func NewProductRepoImpl(db *gorm.DB) repository.ProductRepository {
 
 func (r *productRepoImpl) GetByID(id uint) (*model.Product, error) {
 	var p model.Product
-	if err := r.db.First(&p, id).Error; err != nil {
+	if err := r.db.Preload("Barcodes").Preload("OptionValues").First(&p, id).Error; err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
+func (r *productRepoImpl) GetForUpdate(id uint) (*model.Product, error) {
+	var p model.Product
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *productRepoImpl) GetByIDs(ids []uint) ([]model.Product, error) {
+	var list []model.Product
+	if len(ids) == 0 {
+		return list, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *productRepoImpl) GetBySKU(sku string) (*model.Product, error) {
+	var p model.Product
+	if err := r.db.Where("sku = ?", sku).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *productRepoImpl) GetByBarcode(codes []string) (*model.Product, error) {
+	var p model.Product
+	err := r.db.Preload("Barcodes").Preload("OptionValues").
+		Where("id = (SELECT product_id FROM barcodes WHERE code IN ? LIMIT 1)", codes).
+		First(&p).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *productRepoImpl) ReplaceBarcodes(productID uint, barcodes []model.Barcode) error {
+	if err := r.db.Where("product_id = ?", productID).Delete(&model.Barcode{}).Error; err != nil {
+		return err
+	}
+	if len(barcodes) == 0 {
+		return nil
+	}
+	for i := range barcodes {
+		barcodes[i].ID = 0
+		barcodes[i].ProductID = productID
+	}
+	return r.db.Create(&barcodes).Error
+}
+
 func (r *productRepoImpl) Create(p *model.Product) error {
 	return r.db.Create(p).Error
 }
 
+// Update saves everything but stock and average cost, which only change as stock moves,
+// and none of the associations. Barcodes are replaced with ReplaceBarcodes.
 func (r *productRepoImpl) Update(p *model.Product) error {
-	return r.db.Save(p).Error
+	return r.db.Omit("stock", "average_cost", clause.Associations).Save(p).Error
+}
+
+func (r *productRepoImpl) List(locationID uint) ([]model.Product, error) {
+	var products []model.Product
+	q := r.db.Preload("StockLevels.Location").Preload("Barcodes")
+	if locationID != 0 {
+		q = r.db.Preload("StockLevels", "location_id = ?", locationID).Preload("StockLevels.Location").Preload("Barcodes").
+			Where("is_kit OR EXISTS (SELECT 1 FROM stock_levels WHERE stock_levels.product_id = products.id AND stock_levels.location_id = ? AND stock_levels.quantity > 0)", locationID)
+	}
+	if err := q.Order("id").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepoImpl) ListByCategories(categoryIDs []uint) ([]model.Product, error) {
+	var products []model.Product
+	if err := r.db.Preload("Barcodes").Where("category_id IN ?", categoryIDs).Order("id").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepoImpl) ListVariants(parentID uint) ([]model.Product, error) {
+	var variants []model.Product
+	err := r.db.Preload("Barcodes").Preload("OptionValues").
+		Where("parent_id = ?", parentID).Order("id").Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+func (r *productRepoImpl) ListOptions(productID uint) ([]model.ProductOption, error) {
+	var options []model.ProductOption
+	err := r.db.Preload("Values", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Where("product_id = ?", productID).Order("position, id").Find(&options).Error
+	if err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+func (r *productRepoImpl) CreateOption(o *model.ProductOption) error {
+	return r.db.Omit("Values").Create(o).Error
+}
+
+func (r *productRepoImpl) CreateOptionValue(v *model.ProductOptionValue) error {
+	return r.db.Create(v).Error
 }
 
-func (r *productRepoImpl) List() ([]model.Product, error) {
+func (r *productRepoImpl) SyncVariants(parent *model.Product) error {
+	err := r.db.Model(&model.Product{}).Where("parent_id = ?", parent.ID).Updates(map[string]interface{}{
+		"category_id":        parent.CategoryID,
+		"tax_class_id":       parent.TaxClassID,
+		"price_includes_tax": parent.PriceIncludesTax,
+		"unit":               parent.Unit,
+		"precision":          parent.Precision,
+		"purchase_unit":      parent.PurchaseUnit,
+		"pack_size":          parent.PackSize,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Model(&model.Product{}).
+		Where("parent_id = ? AND NOT price_override", parent.ID).
+		Update("price", parent.Price).Error
+}
+
+func (r *productRepoImpl) ListComponents(kitID uint) ([]model.KitComponent, error) {
+	var components []model.KitComponent
+	if err := r.db.Preload("Component").Where("kit_id = ?", kitID).Order("id").Find(&components).Error; err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+func (r *productRepoImpl) ReplaceComponents(kitID uint, components []model.KitComponent) error {
+	if err := r.db.Where("kit_id = ?", kitID).Delete(&model.KitComponent{}).Error; err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
+	}
+	for i := range components {
+		components[i].ID = 0
+		components[i].KitID = kitID
+	}
+	return r.db.Omit("Component").Create(&components).Error
+}
+
+func (r *productRepoImpl) KitAvailability(kitIDs []uint, locationID uint) (map[uint]model.Quantity, error) {
+	var rows []struct {
+		KitID     uint
+		Available model.Quantity
+	}
+	q := r.db.Table("kit_components").Where("kit_components.kit_id IN ?", kitIDs).Group("kit_components.kit_id")
+	if locationID != 0 {
+		q = q.Joins("LEFT JOIN stock_levels ON stock_levels.product_id = kit_components.component_id AND stock_levels.location_id = ?", locationID).
+			Select("kit_components.kit_id, MIN(FLOOR(COALESCE(stock_levels.quantity, 0) / kit_components.quantity)) AS available")
+	} else {
+		q = q.Joins("JOIN products ON products.id = kit_components.component_id").
+			Select("kit_components.kit_id, MIN(FLOOR(products.stock / kit_components.quantity)) AS available")
+	}
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	available := make(map[uint]model.Quantity, len(rows))
+	for _, row := range rows {
+		available[row.KitID] = row.Available
+	}
+	return available, nil
+}
+
+func (r *productRepoImpl) ListLowStock() ([]model.Product, error) {
 	var products []model.Product
-	if err := r.db.Find(&products).Error; err != nil {
+	if err := r.db.Where("reorder_point > 0 AND stock <= reorder_point AND NOT is_kit AND NOT has_variants").Order("id").Find(&products).Error; err != nil {
 		return nil, err
 	}
 	return products, nil
 }
 
-func (r *productRepoImpl) ReduceStock(productID uint, qty int) error {
+func (r *productRepoImpl) ReduceStock(productID uint, qty model.Quantity) error {
 	res := r.db.Model(&model.Product{}).
 		Where("id = ? AND stock >= ?", productID, qty).
 		Update("stock", gorm.Expr("stock - ?", qty))
@@ -53,6 +220,23 @@ func (r *productRepoImpl) ReduceStock(productID uint, qty int) error {
 	return nil
 }
 
+func (r *productRepoImpl) IncreaseStock(productID uint, qty model.Quantity) error {
+	res := r.db.Model(&model.Product{}).
+		Where("id = ?", productID).
+		Update("stock", gorm.Expr("stock + ?", qty))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *productRepoImpl) SetAverageCost(productID uint, cost model.Money) error {
+	return r.db.Model(&model.Product{}).Where("id = ?", productID).Update("average_cost", cost).Error
+}
+
 func (r *productRepoImpl) Delete(id uint) error {
 	return r.db.Delete(&model.Product{}, id).Error
 }
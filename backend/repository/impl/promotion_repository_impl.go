@@ -0,0 +1,70 @@
+package impl
+
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type promotionRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepoImpl(db *gorm.DB) repository.PromotionRepository {
+	return &promotionRepoImpl{db: db}
+}
+
+func (r *promotionRepoImpl) GetByID(id uint) (*model.Promotion, error) {
+	var p model.Promotion
+	if err := r.db.Preload("Products").First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *promotionRepoImpl) List() ([]model.Promotion, error) {
+	var list []model.Promotion
+	if err := r.db.Preload("Products").Order("priority, id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *promotionRepoImpl) ListActive(at time.Time) ([]model.Promotion, error) {
+	var list []model.Promotion
+	err := r.db.Preload("Products").
+		Where("active = ?", true).
+		Where("starts_at IS NULL OR starts_at <= ?", at).
+		Where("ends_at IS NULL OR ends_at > ?", at).
+		Order("priority, id").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Create links the promotion to its existing products; products themselves are not modified.
+func (r *promotionRepoImpl) Create(p *model.Promotion) error {
+	products := p.Products
+	p.Products = nil
+	if err := r.db.Create(p).Error; err != nil {
+		return err
+	}
+	p.Products = products
+	return r.db.Model(p).Association("Products").Replace(products)
+}
+
+func (r *promotionRepoImpl) Update(p *model.Promotion) error {
+	if err := r.db.Omit(clause.Associations).Save(p).Error; err != nil {
+		return err
+	}
+	return r.db.Model(p).Association("Products").Replace(p.Products)
+}
+
+func (r *promotionRepoImpl) Delete(id uint) error {
+	return r.db.Delete(&model.Promotion{}, id).Error
+}
@@ -0,0 +1,140 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type purchaseOrderRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewPurchaseOrderRepoImpl(db *gorm.DB) repository.PurchaseOrderRepository {
+	return &purchaseOrderRepoImpl{db: db}
+}
+
+func (r *purchaseOrderRepoImpl) GetByID(id uint) (*model.PurchaseOrder, error) {
+	var po model.PurchaseOrder
+	if err := r.db.Preload("Supplier").Preload("Items").Preload("Receipts.Items").First(&po, id).Error; err != nil {
+		return nil, err
+	}
+	return &po, nil
+}
+
+func (r *purchaseOrderRepoImpl) GetForUpdate(id uint) (*model.PurchaseOrder, error) {
+	var po model.PurchaseOrder
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&po, id).Error; err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *purchaseOrderRepoImpl) List(status string) ([]model.PurchaseOrder, error) {
+	var list []model.PurchaseOrder
+	q := r.db.Preload("Supplier").Preload("Items")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("id DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *purchaseOrderRepoImpl) Create(po *model.PurchaseOrder) error {
+	return r.db.Create(po).Error
+}
+
+// Update saves the order's own columns; items and receipts have their own methods.
+func (r *purchaseOrderRepoImpl) Update(po *model.PurchaseOrder) error {
+	return r.db.Omit(clause.Associations).Save(po).Error
+}
+
+func (r *purchaseOrderRepoImpl) ReplaceItems(poID uint, items []model.PurchaseOrderItem) error {
+	if err := r.db.Where("purchase_order_id = ?", poID).Delete(&model.PurchaseOrderItem{}).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	for i := range items {
+		items[i].ID = 0
+		items[i].PurchaseOrderID = poID
+	}
+	return r.db.Create(&items).Error
+}
+
+func (r *purchaseOrderRepoImpl) UpdateStatus(poID uint, from []string, to string) error {
+	res := r.db.Model(&model.PurchaseOrder{}).
+		Where("id = ? AND status IN ?", poID, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("purchase order %d cannot move to status %s", poID, to)
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepoImpl) AddReceivedQuantity(itemID uint, qty model.Quantity) error {
+	res := r.db.Model(&model.PurchaseOrderItem{}).
+		Where("id = ? AND received_quantity + ? <= quantity", itemID, qty).
+		Update("received_quantity", gorm.Expr("received_quantity + ?", qty))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("received quantity exceeds quantity ordered")
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepoImpl) CreateReceipt(gr *model.GoodsReceipt) error {
+	return r.db.Create(gr).Error
+}
+
+func (r *purchaseOrderRepoImpl) OnOrder() (map[uint]model.Quantity, error) {
+	var rows []struct {
+		ProductID uint
+		Quantity  model.Quantity
+	}
+	err := r.db.Table("purchase_order_items").
+		Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_items.purchase_order_id").
+		Select("purchase_order_items.product_id, SUM((purchase_order_items.quantity - purchase_order_items.received_quantity) * purchase_order_items.pack_size) AS quantity").
+		Where("purchase_orders.status IN ?",
+			[]string{model.PurchaseOrderDraft, model.PurchaseOrderSent, model.PurchaseOrderPartiallyReceived}).
+		Group("purchase_order_items.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	onOrder := make(map[uint]model.Quantity, len(rows))
+	for _, row := range rows {
+		onOrder[row.ProductID] = row.Quantity
+	}
+	return onOrder, nil
+}
+
+func (r *purchaseOrderRepoImpl) LastUnitCosts(productIDs []uint) (map[uint]model.Money, error) {
+	costs := make(map[uint]model.Money, len(productIDs))
+	if len(productIDs) == 0 {
+		return costs, nil
+	}
+	var items []model.PurchaseOrderItem
+	err := r.db.Raw("SELECT DISTINCT ON (product_id) * FROM purchase_order_items WHERE product_id IN ? ORDER BY product_id, id DESC", productIDs).
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		costs[it.ProductID] = it.UnitCost.MulRate(int64(model.OneUnit), int64(it.PackSize))
+	}
+	return costs, nil
+}
@@ -0,0 +1,47 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+type refundRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewRefundRepoImpl(db *gorm.DB) repository.RefundRepository {
+	return &refundRepoImpl{db: db}
+}
+
+func (r *refundRepoImpl) Create(refund *model.Refund) error {
+	return r.db.Create(refund).Error
+}
+
+func (r *refundRepoImpl) GetByID(id uint) (*model.Refund, error) {
+	var refund model.Refund
+	if err := r.db.Preload("Items.Taxes").Preload("Tenders").First(&refund, id).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (r *refundRepoImpl) ListByOrder(orderID uint) ([]model.Refund, error) {
+	var list []model.Refund
+	if err := r.db.Preload("Items.Taxes").Preload("Tenders").Where("order_id = ?", orderID).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *refundRepoImpl) CreateTenders(tenders []model.RefundTender) error {
+	if len(tenders) == 0 {
+		return nil
+	}
+	return r.db.Create(&tenders).Error
+}
+
+func (r *refundRepoImpl) SetTenderStatus(id uint, status, failure string) error {
+	return r.db.Model(&model.RefundTender{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "failure": failure}).Error
+}
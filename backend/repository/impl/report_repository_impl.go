@@ -0,0 +1,264 @@
+package impl
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+type reportRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewReportRepoImpl(db *gorm.DB) repository.ReportRepository {
+	return &reportRepoImpl{db: db}
+}
+
+var salesStatuses = []string{
+	model.OrderStatusCompleted,
+	model.OrderStatusPartiallyRefunded,
+	model.OrderStatusRefunded,
+}
+
+// itemCost is the cost of an order line with the ingredients used by its modifiers.
+const itemCost = "order_items.cost + COALESCE((SELECT SUM(order_item_modifiers.cost) FROM order_item_modifiers WHERE order_item_modifiers.order_item_id = order_items.id), 0)"
+
+// between restricts a query to rows whose column falls in [from, to).
+func between(q *gorm.DB, column string, from, to *time.Time) *gorm.DB {
+	if from != nil {
+		q = q.Where(column+" >= ?", *from)
+	}
+	if to != nil {
+		q = q.Where(column+" < ?", *to)
+	}
+	return q
+}
+
+func (r *reportRepoImpl) SalesSummary(from, to *time.Time) (*model.SalesSummary, error) {
+	summary := model.SalesSummary{From: from, To: to}
+
+	var sales struct {
+		OrderCount int64
+		Discounts  model.Money
+		Promotions model.Money
+		Coupons    model.Money
+		GrossSales model.Money
+		TaxSales   model.Money
+	}
+	// parked and voided orders are not sales
+	q := between(r.db.Model(&model.Order{}), "completed_at", from, to).
+		Where("status IN ?", salesStatuses).
+		Select("COUNT(*) AS order_count, COALESCE(SUM(discount_total), 0) AS discounts, COALESCE(SUM(promotion_total), 0) AS promotions, COALESCE(SUM(coupon_discount), 0) AS coupons, COALESCE(SUM(total), 0) AS gross_sales, COALESCE(SUM(tax_total), 0) AS tax_sales")
+	if err := q.Scan(&sales).Error; err != nil {
+		return nil, err
+	}
+
+	var cost struct{ Cost model.Money }
+	q = between(r.db.Table("order_items").Joins("JOIN orders ON orders.id = order_items.order_id"), "orders.completed_at", from, to).
+		Where("orders.status IN ?", salesStatuses).
+		Select("COALESCE(SUM(" + itemCost + "), 0) AS cost")
+	if err := q.Scan(&cost).Error; err != nil {
+		return nil, err
+	}
+
+	// refunds count against the period they were issued in, not the period of the sale
+	var refunds struct {
+		Refunds     model.Money
+		TaxRefunded model.Money
+	}
+	q = between(r.db.Model(&model.Refund{}), "created_at", from, to).
+		Select("COALESCE(SUM(total), 0) AS refunds, COALESCE(SUM(tax_total), 0) AS tax_refunded")
+	if err := q.Scan(&refunds).Error; err != nil {
+		return nil, err
+	}
+	var refundedCost struct{ Cost model.Money }
+	q = between(r.db.Table("refund_items").Joins("JOIN refunds ON refunds.id = refund_items.refund_id"), "refunds.created_at", from, to).
+		Select("COALESCE(SUM(refund_items.cost), 0) AS cost")
+	if err := q.Scan(&refundedCost).Error; err != nil {
+		return nil, err
+	}
+
+	summary.OrderCount = sales.OrderCount
+	summary.Discounts = sales.Discounts
+	summary.Promotions = sales.Promotions
+	summary.Coupons = sales.Coupons
+	summary.GrossSales = sales.GrossSales
+	summary.Refunds = refunds.Refunds
+	summary.NetSales = sales.GrossSales - refunds.Refunds
+	summary.TaxSales = sales.TaxSales
+	summary.TaxRefunded = refunds.TaxRefunded
+	summary.NetTax = sales.TaxSales - refunds.TaxRefunded
+	summary.CostOfGoods = cost.Cost - refundedCost.Cost
+	summary.GrossProfit = summary.NetSales - summary.NetTax - summary.CostOfGoods
+	return &summary, nil
+}
+
+func (r *reportRepoImpl) PaymentsByTender(from, to *time.Time) ([]model.TenderTotal, error) {
+	var list []model.TenderTotal
+	q := r.db.Table("payments").
+		Joins("JOIN orders ON orders.id = payments.order_id").
+		Where("orders.status IN ?", salesStatuses)
+	q = between(q, "orders.completed_at", from, to).
+		Select("payments.tender AS tender, COUNT(*) AS count, COALESCE(SUM(payments.amount), 0) AS amount").
+		Group("payments.tender").
+		Order("payments.tender")
+	if err := q.Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Promotions totals what each promotion took off orders completed in the period.
+func (r *reportRepoImpl) Promotions(from, to *time.Time) ([]model.PromotionTotal, error) {
+	var list []model.PromotionTotal
+	q := r.db.Table("order_item_promotions").
+		Joins("JOIN order_items ON order_items.id = order_item_promotions.order_item_id").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.status IN ?", salesStatuses)
+	q = between(q, "orders.completed_at", from, to).
+		Select("order_item_promotions.promotion_id, order_item_promotions.name, COUNT(DISTINCT orders.id) AS order_count, COALESCE(SUM(order_item_promotions.amount), 0) AS amount").
+		Group("order_item_promotions.promotion_id, order_item_promotions.name").
+		Order("order_item_promotions.promotion_id")
+	if err := q.Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *reportRepoImpl) TaxByRate(from, to *time.Time) ([]model.TaxTotal, error) {
+	type row struct {
+		TaxRateID uint
+		Name      string
+		Rate      model.Percent
+		Amount    model.Money
+	}
+
+	var collected []row
+	q := r.db.Table("order_item_taxes").
+		Joins("JOIN order_items ON order_items.id = order_item_taxes.order_item_id").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.status IN ?", salesStatuses)
+	q = between(q, "orders.completed_at", from, to).
+		Select("order_item_taxes.tax_rate_id, order_item_taxes.name, order_item_taxes.rate, SUM(order_item_taxes.amount) AS amount").
+		Group("order_item_taxes.tax_rate_id, order_item_taxes.name, order_item_taxes.rate")
+	if err := q.Scan(&collected).Error; err != nil {
+		return nil, err
+	}
+
+	var refunded []row
+	q = r.db.Table("refund_item_taxes").
+		Joins("JOIN refund_items ON refund_items.id = refund_item_taxes.refund_item_id").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id")
+	q = between(q, "refunds.created_at", from, to).
+		Select("refund_item_taxes.tax_rate_id, refund_item_taxes.name, refund_item_taxes.rate, SUM(refund_item_taxes.amount) AS amount").
+		Group("refund_item_taxes.tax_rate_id, refund_item_taxes.name, refund_item_taxes.rate")
+	if err := q.Scan(&refunded).Error; err != nil {
+		return nil, err
+	}
+
+	// rates are reported as they were charged, so a renamed or changed rate shows separately
+	type key struct {
+		id   uint
+		name string
+		rate model.Percent
+	}
+	index := map[key]int{}
+	var list []model.TaxTotal
+	entry := func(rw row) *model.TaxTotal {
+		k := key{rw.TaxRateID, rw.Name, rw.Rate}
+		if i, ok := index[k]; ok {
+			return &list[i]
+		}
+		index[k] = len(list)
+		list = append(list, model.TaxTotal{TaxRateID: rw.TaxRateID, Name: rw.Name, Rate: rw.Rate})
+		return &list[len(list)-1]
+	}
+	for _, rw := range collected {
+		entry(rw).Collected += rw.Amount
+	}
+	for _, rw := range refunded {
+		entry(rw).Refunded += rw.Amount
+	}
+	for i := range list {
+		list[i].Net = list[i].Collected - list[i].Refunded
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].TaxRateID < list[j].TaxRateID })
+	return list, nil
+}
+
+func (r *reportRepoImpl) GrossProfit(from, to *time.Time) ([]model.ProductProfit, error) {
+	type row struct {
+		ProductID uint
+		Quantity  model.Quantity
+		Sales     model.Money
+		Cost      model.Money
+	}
+
+	var sold []row
+	q := r.db.Table("order_items").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.status IN ?", salesStatuses)
+	q = between(q, "orders.completed_at", from, to).
+		Select("order_items.product_id, SUM(order_items.quantity) AS quantity, SUM(order_items.net_amount) AS sales, SUM(" + itemCost + ") AS cost").
+		Group("order_items.product_id")
+	if err := q.Scan(&sold).Error; err != nil {
+		return nil, err
+	}
+
+	var refunded []row
+	q = r.db.Table("refund_items").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id")
+	q = between(q, "refunds.created_at", from, to).
+		Select("refund_items.product_id, SUM(refund_items.quantity) AS quantity, SUM(refund_items.amount - refund_items.tax_amount) AS sales, SUM(refund_items.cost) AS cost").
+		Group("refund_items.product_id")
+	if err := q.Scan(&refunded).Error; err != nil {
+		return nil, err
+	}
+
+	index := map[uint]int{}
+	var list []model.ProductProfit
+	entry := func(id uint) *model.ProductProfit {
+		if i, ok := index[id]; ok {
+			return &list[i]
+		}
+		index[id] = len(list)
+		list = append(list, model.ProductProfit{ProductID: id})
+		return &list[len(list)-1]
+	}
+	for _, rw := range sold {
+		e := entry(rw.ProductID)
+		e.Quantity += rw.Quantity
+		e.NetSales += rw.Sales
+		e.CostOfGoods += rw.Cost
+	}
+	for _, rw := range refunded {
+		e := entry(rw.ProductID)
+		e.Quantity -= rw.Quantity
+		e.NetSales -= rw.Sales
+		e.CostOfGoods -= rw.Cost
+	}
+
+	var products []model.Product
+	if err := r.db.Select("id, name").Where("id IN ?", append([]uint{0}, keys(index)...)).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		list[index[p.ID]].Name = p.Name
+	}
+	for i := range list {
+		list[i].GrossProfit = list[i].NetSales - list[i].CostOfGoods
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ProductID < list[j].ProductID })
+	return list, nil
+}
+
+func keys(m map[uint]int) []uint {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
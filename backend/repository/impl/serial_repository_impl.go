@@ -0,0 +1,111 @@
+package impl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type serialRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewSerialRepoImpl(db *gorm.DB) repository.SerialRepository {
+	return &serialRepoImpl{db: db}
+}
+
+func (r *serialRepoImpl) Find(productID uint, number string) (*model.Serial, error) {
+	var s model.Serial
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND number = ?", productID, number).
+		First(&s).Error
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *serialRepoImpl) Create(s *model.Serial) error {
+	return r.db.Create(s).Error
+}
+
+func (r *serialRepoImpl) ListByNumber(number string) ([]model.Serial, error) {
+	var list []model.Serial
+	if err := r.db.Where("number = ?", number).Order("product_id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *serialRepoImpl) ListByProduct(productID uint, status string) ([]model.Serial, error) {
+	q := r.db.Where("product_id = ?", productID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	var list []model.Serial
+	if err := q.Order("number").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *serialRepoImpl) ListByMovement(movementID uint) ([]model.Serial, error) {
+	var list []model.Serial
+	err := r.db.Joins("JOIN serial_movements ON serial_movements.serial_id = serials.id").
+		Where("serial_movements.stock_movement_id = ?", movementID).
+		Order("serials.number").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *serialRepoImpl) UpdateState(s *model.Serial, from string) error {
+	s.UpdatedAt = time.Now()
+	res := r.db.Model(&model.Serial{}).
+		Where("id = ? AND status = ?", s.ID, from).
+		Updates(map[string]interface{}{
+			"status":      s.Status,
+			"location_id": s.LocationID,
+			"order_id":    s.OrderID,
+			"updated_at":  s.UpdatedAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("serial was changed by someone else")
+	}
+	return nil
+}
+
+func (r *serialRepoImpl) AddMovement(serialID, movementID uint) error {
+	return r.db.Create(&model.SerialMovement{SerialID: serialID, StockMovementID: movementID}).Error
+}
+
+// Events finds the order behind each movement directly for sales and voids, and through
+// the refund for refunds.
+func (r *serialRepoImpl) Events(serialID uint) ([]model.SerialEvent, error) {
+	var list []model.SerialEvent
+	err := r.db.Table("serial_movements").
+		Select(`stock_movements.id AS stock_movement_id, stock_movements.type, stock_movements.location_id,
+			stock_movements.ref_type, stock_movements.ref_id, orders.id AS order_id,
+			customers.id AS customer_id, COALESCE(customers.name, '') AS customer_name,
+			stock_movements.user_id, stock_movements.created_at`).
+		Joins("JOIN stock_movements ON stock_movements.id = serial_movements.stock_movement_id").
+		Joins("LEFT JOIN refunds ON stock_movements.ref_type = 'refund' AND refunds.id = stock_movements.ref_id").
+		Joins("LEFT JOIN orders ON orders.id = CASE WHEN stock_movements.ref_type = 'order' THEN stock_movements.ref_id ELSE refunds.order_id END").
+		Joins("LEFT JOIN customers ON customers.id = orders.customer_id").
+		Where("serial_movements.serial_id = ?", serialID).
+		Order("stock_movements.id").
+		Scan(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
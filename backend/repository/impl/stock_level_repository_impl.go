@@ -0,0 +1,98 @@
+package impl
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockLevelRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewStockLevelRepoImpl(db *gorm.DB) repository.StockLevelRepository {
+	return &stockLevelRepoImpl{db: db}
+}
+
+func (r *stockLevelRepoImpl) ListByProduct(productID uint) ([]model.StockLevel, error) {
+	var list []model.StockLevel
+	if err := r.db.Preload("Location").Where("product_id = ?", productID).Order("location_id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stockLevelRepoImpl) ListByLocation(locationID uint) ([]model.StockLevel, error) {
+	var list []model.StockLevel
+	if err := r.db.Where("location_id = ?", locationID).Order("product_id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stockLevelRepoImpl) HasStock(locationID uint) (bool, error) {
+	var n int64
+	if err := r.db.Model(&model.StockLevel{}).Where("location_id = ? AND quantity <> 0", locationID).Count(&n).Error; err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *stockLevelRepoImpl) Reduce(productID, locationID uint, qty model.Quantity) error {
+	res := r.db.Model(&model.StockLevel{}).
+		Where("product_id = ? AND location_id = ? AND quantity >= ?", productID, locationID, qty).
+		Update("quantity", gorm.Expr("quantity - ?", qty))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("insufficient stock at location")
+	}
+	return nil
+}
+
+// Increase creates the level the first time a location receives the product.
+func (r *stockLevelRepoImpl) Increase(productID, locationID uint, qty model.Quantity) error {
+	level := model.StockLevel{ProductID: productID, LocationID: locationID, Quantity: qty}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "location_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"quantity": gorm.Expr("stock_levels.quantity + ?", qty)}),
+	}).Create(&level).Error
+}
+
+// lockBatch keeps the product list of one statement well under the bind parameter limit.
+const lockBatch = 1000
+
+func (r *stockLevelRepoImpl) Lock(locationID uint, productIDs []uint) (map[uint]model.Quantity, error) {
+	ids := append([]uint(nil), productIDs...)
+	// lock in product order, so two callers locking overlapping products cannot deadlock
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	quantities := make(map[uint]model.Quantity, len(ids))
+	for start := 0; start < len(ids); start += lockBatch {
+		batch := ids[start:min(start+lockBatch, len(ids))]
+		levels := make([]model.StockLevel, len(batch))
+		for i, id := range batch {
+			levels[i] = model.StockLevel{ProductID: id, LocationID: locationID}
+		}
+		// a level being created by a first receipt holds this insert until it commits
+		if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&levels).Error; err != nil {
+			return nil, err
+		}
+		var locked []model.StockLevel
+		err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("location_id = ? AND product_id IN ?", locationID, batch).
+			Order("product_id").
+			Find(&locked).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range locked {
+			quantities[l.ProductID] = l.Quantity
+		}
+	}
+	return quantities, nil
+}
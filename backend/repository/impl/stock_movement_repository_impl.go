@@ -0,0 +1,70 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+type stockMovementRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepoImpl(db *gorm.DB) repository.StockMovementRepository {
+	return &stockMovementRepoImpl{db: db}
+}
+
+func (r *stockMovementRepoImpl) Create(m *model.StockMovement) error {
+	return r.db.Create(m).Error
+}
+
+func (r *stockMovementRepoImpl) ListByProduct(productID uint) ([]model.StockMovement, error) {
+	var list []model.StockMovement
+	if err := r.db.Where("product_id = ?", productID).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stockMovementRepoImpl) ListByRef(refType string, refID uint) ([]model.StockMovement, error) {
+	var list []model.StockMovement
+	if err := r.db.Where("ref_type = ? AND ref_id = ?", refType, refID).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stockMovementRepoImpl) LastID() (uint, error) {
+	var id uint
+	if err := r.db.Model(&model.StockMovement{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *stockMovementRepoImpl) ListByLocationSince(locationID, afterID uint) ([]model.StockMovement, error) {
+	var list []model.StockMovement
+	if err := r.db.Where("location_id = ? AND id > ?", locationID, afterID).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stockMovementRepoImpl) Reconcile(productID uint) ([]model.StockReconciliation, error) {
+	var list []model.StockReconciliation
+	ledger := r.db.Table("stock_movements").
+		Select("product_id, SUM(quantity) AS total").
+		Group("product_id")
+	q := r.db.Table("products").
+		Joins("LEFT JOIN (?) AS ledger ON ledger.product_id = products.id", ledger).
+		Select("products.id AS product_id, products.name, products.stock, " +
+			"COALESCE(ledger.total, 0) AS ledger_total, products.stock - COALESCE(ledger.total, 0) AS difference").
+		Order("products.id")
+	if productID != 0 {
+		q = q.Where("products.id = ?", productID)
+	}
+	if err := q.Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
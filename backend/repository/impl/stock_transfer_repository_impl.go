@@ -0,0 +1,61 @@
+package impl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockTransferRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewStockTransferRepoImpl(db *gorm.DB) repository.StockTransferRepository {
+	return &stockTransferRepoImpl{db: db}
+}
+
+func (r *stockTransferRepoImpl) GetByID(id uint) (*model.StockTransfer, error) {
+	var t model.StockTransfer
+	if err := r.db.Preload("Items").First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *stockTransferRepoImpl) List(status string) ([]model.StockTransfer, error) {
+	var list []model.StockTransfer
+	q := r.db.Preload("Items")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("id DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stockTransferRepoImpl) Create(t *model.StockTransfer) error {
+	return r.db.Create(t).Error
+}
+
+// Update saves the transfer's own columns; its items do not change after it is created.
+func (r *stockTransferRepoImpl) Update(t *model.StockTransfer) error {
+	return r.db.Omit(clause.Associations).Save(t).Error
+}
+
+func (r *stockTransferRepoImpl) UpdateStatus(id uint, from []string, to string) error {
+	res := r.db.Model(&model.StockTransfer{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("stock transfer %d cannot move to status %s", id, to)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stocktakeRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewStocktakeRepoImpl(db *gorm.DB) repository.StocktakeRepository {
+	return &stocktakeRepoImpl{db: db}
+}
+
+func (r *stocktakeRepoImpl) GetByID(id uint) (*model.Stocktake, error) {
+	var st model.Stocktake
+	if err := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("product_id") }).First(&st, id).Error; err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (r *stocktakeRepoImpl) List(status string) ([]model.Stocktake, error) {
+	var list []model.Stocktake
+	q := r.db
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("id DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stocktakeRepoImpl) Create(st *model.Stocktake) error {
+	return r.db.Create(st).Error
+}
+
+// Update saves the session's own columns; lines have their own methods.
+func (r *stocktakeRepoImpl) Update(st *model.Stocktake) error {
+	return r.db.Omit(clause.Associations).Save(st).Error
+}
+
+func (r *stocktakeRepoImpl) UpdateStatus(id uint, from []string, to string) error {
+	res := r.db.Model(&model.Stocktake{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("stocktake %d cannot move to status %s", id, to)
+	}
+	return nil
+}
+
+func (r *stocktakeRepoImpl) Lock(id uint) error {
+	res := r.db.Model(&model.Stocktake{}).
+		Where("id = ? AND status = ?", id, model.StocktakeOpen).
+		Update("updated_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("stocktake %d is not open", id)
+	}
+	return nil
+}
+
+func (r *stocktakeRepoImpl) AddCount(stocktakeID, productID uint, qty model.Quantity, mark uint) error {
+	res := r.db.Model(&model.StocktakeLine{}).
+		Where("stocktake_id = ? AND product_id = ? AND COALESCE(counted, 0) + ? >= 0", stocktakeID, productID, qty).
+		Updates(map[string]interface{}{
+			"counted":    gorm.Expr("COALESCE(counted, 0) + ?", qty),
+			"count_mark": mark,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("product is not in the stocktake or the count would go below zero")
+	}
+	return nil
+}
+
+func (r *stocktakeRepoImpl) CreateCount(c *model.StocktakeCount) error {
+	return r.db.Create(c).Error
+}
+
+func (r *stocktakeRepoImpl) ListCounts(stocktakeID uint) ([]model.StocktakeCount, error) {
+	var list []model.StocktakeCount
+	if err := r.db.Where("stocktake_id = ?", stocktakeID).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *stocktakeRepoImpl) UpdateLine(l *model.StocktakeLine) error {
+	return r.db.Save(l).Error
+}
@@ -0,0 +1,43 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+type supplierRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewSupplierRepoImpl(db *gorm.DB) repository.SupplierRepository {
+	return &supplierRepoImpl{db: db}
+}
+
+func (r *supplierRepoImpl) GetByID(id uint) (*model.Supplier, error) {
+	var s model.Supplier
+	if err := r.db.First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *supplierRepoImpl) List() ([]model.Supplier, error) {
+	var list []model.Supplier
+	if err := r.db.Order("name").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *supplierRepoImpl) Create(s *model.Supplier) error {
+	return r.db.Create(s).Error
+}
+
+func (r *supplierRepoImpl) Update(s *model.Supplier) error {
+	return r.db.Save(s).Error
+}
+
+func (r *supplierRepoImpl) Delete(id uint) error {
+	return r.db.Delete(&model.Supplier{}, id).Error
+}
@@ -0,0 +1,93 @@
+package impl
+
+import (
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type taxRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewTaxRepoImpl(db *gorm.DB) repository.TaxRepository {
+	return &taxRepoImpl{db: db}
+}
+
+func (r *taxRepoImpl) GetRate(id uint) (*model.TaxRate, error) {
+	var rate model.TaxRate
+	if err := r.db.First(&rate, id).Error; err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *taxRepoImpl) GetRates(ids []uint) ([]model.TaxRate, error) {
+	var list []model.TaxRate
+	if len(ids) == 0 {
+		return list, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *taxRepoImpl) ListRates() ([]model.TaxRate, error) {
+	var list []model.TaxRate
+	if err := r.db.Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *taxRepoImpl) CreateRate(rate *model.TaxRate) error {
+	return r.db.Create(rate).Error
+}
+
+func (r *taxRepoImpl) UpdateRate(rate *model.TaxRate) error {
+	return r.db.Save(rate).Error
+}
+
+func (r *taxRepoImpl) DeleteRate(id uint) error {
+	return r.db.Delete(&model.TaxRate{}, id).Error
+}
+
+func (r *taxRepoImpl) GetClass(id uint) (*model.TaxClass, error) {
+	var c model.TaxClass
+	if err := r.db.Preload("Rates").First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *taxRepoImpl) ListClasses() ([]model.TaxClass, error) {
+	var list []model.TaxClass
+	if err := r.db.Preload("Rates").Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// CreateClass links the class to its existing rates; rates themselves are not modified.
+func (r *taxRepoImpl) CreateClass(c *model.TaxClass) error {
+	rates := c.Rates
+	c.Rates = nil
+	if err := r.db.Create(c).Error; err != nil {
+		return err
+	}
+	c.Rates = rates
+	return r.db.Model(c).Association("Rates").Replace(rates)
+}
+
+func (r *taxRepoImpl) UpdateClass(c *model.TaxClass) error {
+	if err := r.db.Omit(clause.Associations).Save(c).Error; err != nil {
+		return err
+	}
+	return r.db.Model(c).Association("Rates").Replace(c.Rates)
+}
+
+func (r *taxRepoImpl) DeleteClass(id uint) error {
+	return r.db.Delete(&model.TaxClass{}, id).Error
+}
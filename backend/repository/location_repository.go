@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type LocationRepository interface {
+	GetByID(id uint) (*model.Location, error)
+	GetDefault() (*model.Location, error)
+	List() ([]model.Location, error)
+	Create(l *model.Location) error
+	Update(l *model.Location) error
+	// ClearDefault unsets the default flag on every location but keepID.
+	ClearDefault(keepID uint) error
+	Delete(id uint) error
+}
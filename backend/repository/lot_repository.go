@@ -0,0 +1,23 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type LotRepository interface {
+	GetByID(id uint) (*model.Lot, error)
+	Find(productID, locationID uint, number string) (*model.Lot, error)
+	// FindOrCreate returns the product's lot with the number at the location, creating it
+	// empty the first time the location receives it.
+	FindOrCreate(lot *model.Lot) error
+	ListByProduct(productID uint) ([]model.Lot, error)
+	// ListOpen lists the lots of a product at a location that hold stock, expiring first
+	// first and locked for update. With sellable only unexpired lots are listed.
+	ListOpen(productID, locationID uint, sellable bool) ([]model.Lot, error)
+	// LastExpiring is the lot of ids that expires last, a lot that never expires first.
+	LastExpiring(ids []uint) (*model.Lot, error)
+	// ListExpiring lists lots holding stock that expire within days, including those
+	// already expired, at one location or everywhere when locationID is 0.
+	ListExpiring(days int, locationID uint) ([]model.Lot, error)
+	// Reduce fails if the lot does not hold qty.
+	Reduce(id uint, qty model.Quantity) error
+	Increase(id uint, qty model.Quantity) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type ModifierRepository interface {
+	GetGroup(id uint) (*model.ModifierGroup, error)
+	GetGroups(ids []uint) ([]model.ModifierGroup, error)
+	ListGroups() ([]model.ModifierGroup, error)
+	// ListByProduct returns the groups offered on a product, with their modifiers.
+	ListByProduct(productID uint) ([]model.ModifierGroup, error)
+	CreateGroup(g *model.ModifierGroup) error
+	// UpdateGroup saves the group and its modifiers and removes the modifiers it no longer has.
+	UpdateGroup(g *model.ModifierGroup) error
+	DeleteGroup(id uint) error
+	SetProductGroups(productID uint, groups []model.ModifierGroup) error
+}
@@ -1,9 +1,27 @@
 package repository
 
-import "github.com/nawodahansani/pos-backend/model"
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
 
 type OrderRepository interface {
 	CreateOrder(order *model.Order) error
 	GetByID(id uint) (*model.Order, error)
-	List() ([]model.Order, error)
+	List(status string) ([]model.Order, error)
+	Update(order *model.Order) error
+	ReplaceItems(orderID uint, items []model.OrderItem) error
+	CreatePayments(orderID uint, payments []model.Payment) error
+	AddPaymentRefund(paymentID uint, amount model.Money) error
+	SetPaymentStatus(paymentID uint, status, failure string) error
+	UpdateStatus(orderID uint, from []string, to string) error
+	AddRefundedQuantity(orderItemID uint, qty model.Quantity) error
+	SetItemCost(orderItemID uint, cost model.Money) error
+	SetModifierCost(orderItemModifierID uint, cost model.Money) error
+	SetComponentCost(orderItemComponentID uint, cost model.Money) error
+	AddRefundedTotal(orderID uint, amount model.Money) error
+	// UnitsSold sums the quantities sold, less refunds, on orders completed since the
+	// given time, by product.
+	UnitsSold(since time.Time) (map[uint]model.Quantity, error)
 }
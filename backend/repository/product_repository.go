@@ -4,9 +4,40 @@ import "github.com/nawodahansani/pos-backend/model"
 
 type ProductRepository interface {
 	GetByID(id uint) (*model.Product, error)
+	// GetForUpdate reads the product after locking its row until the transaction ends. Stock
+	// changes lock the row too, so checks made on what it reads hold until the commit.
+	GetForUpdate(id uint) (*model.Product, error)
+	GetByIDs(ids []uint) ([]model.Product, error)
+	GetBySKU(sku string) (*model.Product, error)
+	// GetByBarcode returns the product with any of the codes.
+	GetByBarcode(codes []string) (*model.Product, error)
+	ReplaceBarcodes(productID uint, barcodes []model.Barcode) error
 	Create(p *model.Product) error
 	Update(p *model.Product) error
-	List() ([]model.Product, error)
-	ReduceStock(productID uint, qty int) error
+	// List returns every product with its stock levels, or with locationID only the
+	// products stocked there and their level at that location.
+	List(locationID uint) ([]model.Product, error)
+	ListByCategories(categoryIDs []uint) ([]model.Product, error)
+	// ListVariants returns a product's variants with their option values.
+	ListVariants(parentID uint) ([]model.Product, error)
+	// ListOptions returns a product's options with their values, in order.
+	ListOptions(productID uint) ([]model.ProductOption, error)
+	CreateOption(o *model.ProductOption) error
+	CreateOptionValue(v *model.ProductOptionValue) error
+	// SyncVariants copies the parent's category, tax class, tax-inclusive pricing and unit
+	// to its variants, and its price to those without a price of their own.
+	SyncVariants(parent *model.Product) error
+	// ListComponents returns a kit's bill of materials with the component products.
+	ListComponents(kitID uint) ([]model.KitComponent, error)
+	ReplaceComponents(kitID uint, components []model.KitComponent) error
+	// KitAvailability returns how many of each kit its components in stock make, at
+	// locationID or, when it is 0, across all locations.
+	KitAvailability(kitIDs []uint, locationID uint) (map[uint]model.Quantity, error)
+	// ListLowStock returns the products at or below their reorder point. Kits and products
+	// with variants hold no stock to reorder.
+	ListLowStock() ([]model.Product, error)
+	ReduceStock(productID uint, qty model.Quantity) error
+	IncreaseStock(productID uint, qty model.Quantity) error
+	SetAverageCost(productID uint, cost model.Money) error
 	Delete(id uint) error 
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+type PromotionRepository interface {
+	GetByID(id uint) (*model.Promotion, error)
+	List() ([]model.Promotion, error)
+	// ListActive returns active promotions whose schedule includes at. Daily time windows
+	// are left for the caller to check.
+	ListActive(at time.Time) ([]model.Promotion, error)
+	Create(p *model.Promotion) error
+	Update(p *model.Promotion) error
+	Delete(id uint) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type PurchaseOrderRepository interface {
+	GetByID(id uint) (*model.PurchaseOrder, error)
+	// GetForUpdate reads the order after locking its row until the transaction ends, so
+	// deliveries received at the same time see each other's received quantities.
+	GetForUpdate(id uint) (*model.PurchaseOrder, error)
+	List(status string) ([]model.PurchaseOrder, error)
+	Create(po *model.PurchaseOrder) error
+	Update(po *model.PurchaseOrder) error
+	ReplaceItems(poID uint, items []model.PurchaseOrderItem) error
+	// UpdateStatus moves the order to status "to" only if it is in one of "from".
+	UpdateStatus(poID uint, from []string, to string) error
+	// AddReceivedQuantity fails if it would receive more than was ordered.
+	AddReceivedQuantity(itemID uint, qty model.Quantity) error
+	CreateReceipt(r *model.GoodsReceipt) error
+	// OnOrder sums what is still to be received on draft and open orders, by product, in
+	// the product's own unit.
+	OnOrder() (map[uint]model.Quantity, error)
+	// LastUnitCosts returns the cost per unit of each product when it was last ordered, its
+	// pack cost divided by the pack size.
+	LastUnitCosts(productIDs []uint) (map[uint]model.Money, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type RefundRepository interface {
+	Create(r *model.Refund) error
+	GetByID(id uint) (*model.Refund, error)
+	ListByOrder(orderID uint) ([]model.Refund, error)
+	// CreateTenders records tenders given back without a refund, when an order is voided.
+	CreateTenders(tenders []model.RefundTender) error
+	SetTenderStatus(id uint, status, failure string) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+type ReportRepository interface {
+	SalesSummary(from, to *time.Time) (*model.SalesSummary, error)
+	PaymentsByTender(from, to *time.Time) ([]model.TenderTotal, error)
+	TaxByRate(from, to *time.Time) ([]model.TaxTotal, error)
+	Promotions(from, to *time.Time) ([]model.PromotionTotal, error)
+	GrossProfit(from, to *time.Time) ([]model.ProductProfit, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type SerialRepository interface {
+	// Find returns the product's serial with the number, locked for update.
+	Find(productID uint, number string) (*model.Serial, error)
+	Create(s *model.Serial) error
+	ListByNumber(number string) ([]model.Serial, error)
+	ListByProduct(productID uint, status string) ([]model.Serial, error)
+	// ListByMovement lists the serials moved by a stock movement.
+	ListByMovement(movementID uint) ([]model.Serial, error)
+	// UpdateState moves the serial on from status from, failing if it has moved meanwhile.
+	UpdateState(s *model.Serial, from string) error
+	AddMovement(serialID, movementID uint) error
+	Events(serialID uint) ([]model.SerialEvent, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type StockLevelRepository interface {
+	ListByProduct(productID uint) ([]model.StockLevel, error)
+	ListByLocation(locationID uint) ([]model.StockLevel, error)
+	// HasStock reports whether any product has stock at the location.
+	HasStock(locationID uint) (bool, error)
+	// Reduce fails if the location does not hold qty of the product.
+	Reduce(productID, locationID uint, qty model.Quantity) error
+	Increase(productID, locationID uint, qty model.Quantity) error
+	// Lock locks the products' levels at the location until the transaction ends and
+	// returns their quantities, creating an empty level for any product the location has
+	// not held. Stock movements update the level before they are written, so Lock waits
+	// for those in flight and later ones wait for the transaction.
+	Lock(locationID uint, productIDs []uint) (map[uint]model.Quantity, error)
+}
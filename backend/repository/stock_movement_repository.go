@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type StockMovementRepository interface {
+	Create(m *model.StockMovement) error
+	ListByProduct(productID uint) ([]model.StockMovement, error)
+	// ListByRef lists the movements written for a document, e.g. "order" 42.
+	ListByRef(refType string, refID uint) ([]model.StockMovement, error)
+	// LastID is the id of the latest movement, 0 when there is none.
+	LastID() (uint, error)
+	// ListByLocationSince lists the movements at a location after movement afterID.
+	ListByLocationSince(locationID, afterID uint) ([]model.StockMovement, error)
+	// Reconcile compares stock with the ledger for one product, or for every product
+	// when productID is 0.
+	Reconcile(productID uint) ([]model.StockReconciliation, error)
+}
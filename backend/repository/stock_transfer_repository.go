@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type StockTransferRepository interface {
+	GetByID(id uint) (*model.StockTransfer, error)
+	List(status string) ([]model.StockTransfer, error)
+	Create(t *model.StockTransfer) error
+	Update(t *model.StockTransfer) error
+	// UpdateStatus moves the transfer to status "to" only if it is in one of "from".
+	UpdateStatus(id uint, from []string, to string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type StocktakeRepository interface {
+	GetByID(id uint) (*model.Stocktake, error)
+	List(status string) ([]model.Stocktake, error)
+	Create(st *model.Stocktake) error
+	Update(st *model.Stocktake) error
+	// UpdateStatus moves the session to status "to" only if it is in one of "from".
+	UpdateStatus(id uint, from []string, to string) error
+	// Lock fails unless the session is open, and holds it until the transaction ends so
+	// it cannot be posted while counts are being added.
+	Lock(id uint) error
+	// AddCount adds to a line's counted quantity and moves its count mark; the total may
+	// not go below zero.
+	AddCount(stocktakeID, productID uint, qty model.Quantity, mark uint) error
+	CreateCount(c *model.StocktakeCount) error
+	ListCounts(stocktakeID uint) ([]model.StocktakeCount, error)
+	UpdateLine(l *model.StocktakeLine) error
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type SupplierRepository interface {
+	GetByID(id uint) (*model.Supplier, error)
+	List() ([]model.Supplier, error)
+	Create(s *model.Supplier) error
+	Update(s *model.Supplier) error
+	Delete(id uint) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/nawodahansani/pos-backend/model"
+
+type TaxRepository interface {
+	GetRate(id uint) (*model.TaxRate, error)
+	GetRates(ids []uint) ([]model.TaxRate, error)
+	ListRates() ([]model.TaxRate, error)
+	CreateRate(r *model.TaxRate) error
+	UpdateRate(r *model.TaxRate) error
+	DeleteRate(id uint) error
+
+	GetClass(id uint) (*model.TaxClass, error)
+	ListClasses() ([]model.TaxClass, error)
+	CreateClass(c *model.TaxClass) error
+	UpdateClass(c *model.TaxClass) error
+	DeleteClass(id uint) error
+}
@@ -46,7 +46,7 @@ func (s *authService) Register(userDto dto.RegisterRequest) (model.UserResponse,
 		LastName:  userDto.LastName,
 		Email:     userDto.Email,
 		Password:  string(hashedPassword),
-		Role:      "user",
+		Role:      model.RoleUser,
 	}
 
 	createdUser, err := s.userRepo.Create(user)
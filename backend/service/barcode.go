@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"gorm.io/gorm"
+)
+
+// validateBarcode checks the length and character set of a code for its type and the
+// check digit of EAN-13 and UPC-A codes. A scale code is the prefix and item code its
+// labels start with.
+func validateBarcode(b dto.BarcodeDTO) error {
+	switch b.Type {
+	case model.BarcodeEAN13, model.BarcodeUPCA:
+		n := 13
+		if b.Type == model.BarcodeUPCA {
+			n = 12
+		}
+		if len(b.Code) != n || !allDigits(b.Code) {
+			return fmt.Errorf("%s barcode %q must be %d digits", b.Type, b.Code, n)
+		}
+		if !gtinCheckDigitValid(b.Code) {
+			return fmt.Errorf("%s barcode %q has a wrong check digit", b.Type, b.Code)
+		}
+	case model.BarcodeScale:
+		if len(b.Code) != 7 || !allDigits(b.Code) || b.Code[0] != '2' {
+			return fmt.Errorf("scale barcode %q must be 7 digits starting with 2", b.Code)
+		}
+	case model.BarcodeCode128:
+		if len(b.Code) == 0 || len(b.Code) > 80 {
+			return fmt.Errorf("code128 barcode %q must be 1 to 80 characters", b.Code)
+		}
+		for _, c := range b.Code {
+			if c < 32 || c > 126 {
+				return fmt.Errorf("code128 barcode %q may only hold printable ASCII", b.Code)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown barcode type %q", b.Type)
+	}
+	return nil
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// gtinCheckDigitValid checks the last digit of an EAN or UPC code: the other digits are
+// weighted 3 and 1 alternately from the right and the check digit brings the sum to a
+// multiple of ten.
+func gtinCheckDigitValid(code string) bool {
+	sum := 0
+	for i, weight := len(code)-2, 3; i >= 0; i, weight = i-1, 4-weight {
+		sum += int(code[i]-'0') * weight
+	}
+	return int(code[len(code)-1]-'0') == (10-sum%10)%10
+}
+
+// barcodeCandidates is the code as scanned plus the same GTIN written the other way: a
+// UPC-A code and the EAN-13 code with a leading zero are one barcode.
+func barcodeCandidates(code string) []string {
+	codes := []string{code}
+	if !allDigits(code) {
+		return codes
+	}
+	switch {
+	case len(code) == 13 && code[0] == '0':
+		codes = append(codes, code[1:])
+	case len(code) == 12:
+		codes = append(codes, "0"+code)
+	}
+	return codes
+}
+
+// buildBarcodes validates the barcodes of product productID, 0 for a new one, and checks
+// that no other product has them or the SKU.
+func buildBarcodes(prodRepo repository.ProductRepository, productID uint, sku string, input []dto.BarcodeDTO) ([]model.Barcode, error) {
+	if sku != "" {
+		p, err := prodRepo.GetBySKU(sku)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if p != nil && p.ID != productID {
+			return nil, fmt.Errorf("SKU %s is already used by product %d", sku, p.ID)
+		}
+	}
+
+	seen := map[string]bool{}
+	barcodes := make([]model.Barcode, 0, len(input))
+	for _, b := range input {
+		if err := validateBarcode(b); err != nil {
+			return nil, err
+		}
+		for _, code := range barcodeCandidates(b.Code) {
+			if seen[code] {
+				return nil, fmt.Errorf("barcode %s is given twice", b.Code)
+			}
+			seen[code] = true
+		}
+		p, err := prodRepo.GetByBarcode(barcodeCandidates(b.Code))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if p != nil && p.ID != productID {
+			return nil, fmt.Errorf("barcode %s is already used by product %d", b.Code, p.ID)
+		}
+		barcodes = append(barcodes, model.Barcode{Code: b.Code, Type: b.Type})
+	}
+	return barcodes, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+func TestGTINCheckDigitValid(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"4006381333931", true},
+		{"4006381333932", false},
+		{"036000291452", true},
+		{"0036000291452", true},
+		{"036000291453", false},
+		{"012345678905", true},
+		{"96385074", true},
+		{"0000000000000", true},
+	}
+	for _, tt := range tests {
+		if got := gtinCheckDigitValid(tt.code); got != tt.want {
+			t.Errorf("gtinCheckDigitValid(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+// A UPC-A code and the EAN-13 code with a leading zero have the same check digit.
+func TestGTINCheckDigitUPCAsEAN(t *testing.T) {
+	for _, upc := range []string{"036000291452", "012345678905", "725272730706", "036000291453"} {
+		if gtinCheckDigitValid(upc) != gtinCheckDigitValid("0"+upc) {
+			t.Errorf("%q and %q disagree on the check digit", upc, "0"+upc)
+		}
+	}
+}
+
+func TestBarcodeCandidates(t *testing.T) {
+	tests := []struct {
+		code string
+		want []string
+	}{
+		{"036000291452", []string{"036000291452", "0036000291452"}},
+		{"0036000291452", []string{"0036000291452", "036000291452"}},
+		{"4006381333931", []string{"4006381333931"}},
+		{"96385074", []string{"96385074"}},
+		{"ABC-12345678", []string{"ABC-12345678"}},
+	}
+	for _, tt := range tests {
+		if got := barcodeCandidates(tt.code); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("barcodeCandidates(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestValidateBarcode(t *testing.T) {
+	tests := []struct {
+		code    string
+		typ     string
+		wantErr bool
+	}{
+		{"4006381333931", model.BarcodeEAN13, false},
+		{"4006381333932", model.BarcodeEAN13, true},
+		{"036000291452", model.BarcodeEAN13, true},
+		{"036000291452", model.BarcodeUPCA, false},
+		{"0036000291452", model.BarcodeUPCA, true},
+		{"03600029145A", model.BarcodeUPCA, true},
+		{"2112345", model.BarcodeScale, false},
+		{"1112345", model.BarcodeScale, true},
+		{"211234", model.BarcodeScale, true},
+		{"ABC-123 x", model.BarcodeCode128, false},
+		{"", model.BarcodeCode128, true},
+		{"café", model.BarcodeCode128, true},
+		{"4006381333931", "qr", true},
+	}
+	for _, tt := range tests {
+		err := validateBarcode(dto.BarcodeDTO{Code: tt.code, Type: tt.typ})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateBarcode(%q, %s) = %v, want error %v", tt.code, tt.typ, err, tt.wantErr)
+		}
+	}
+}
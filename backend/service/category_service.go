@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+type CategoryService interface {
+	CreateCategory(input dto.CreateCategoryDTO) (*model.Category, error)
+	List() ([]model.Category, error)
+	Tree() ([]model.Category, error)
+	GetByID(id uint) (*model.Category, error)
+	ListProducts(id uint) ([]model.Product, error)
+	UpdateCategory(id uint, input dto.CreateCategoryDTO) (*model.Category, error)
+	DeleteCategory(id uint) error
+}
+
+type categoryServiceImpl struct {
+	categoryRepo repository.CategoryRepository
+	prodRepo     repository.ProductRepository
+	taxRepo      repository.TaxRepository
+}
+
+func NewCategoryService(cr repository.CategoryRepository, pr repository.ProductRepository, tr repository.TaxRepository) CategoryService {
+	return &categoryServiceImpl{categoryRepo: cr, prodRepo: pr, taxRepo: tr}
+}
+
+func (s *categoryServiceImpl) CreateCategory(input dto.CreateCategoryDTO) (*model.Category, error) {
+	c := model.Category{}
+	if err := s.fill(&c, input); err != nil {
+		return nil, err
+	}
+	if err := s.categoryRepo.Create(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *categoryServiceImpl) List() ([]model.Category, error) {
+	return s.categoryRepo.List()
+}
+
+// Tree returns the top-level categories with their subcategories nested under them.
+func (s *categoryServiceImpl) Tree() ([]model.Category, error) {
+	list, err := s.categoryRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	return nestCategories(list), nil
+}
+
+func (s *categoryServiceImpl) GetByID(id uint) (*model.Category, error) {
+	return s.categoryRepo.GetByID(id)
+}
+
+// ListProducts lists the products in the category or any of its subcategories.
+func (s *categoryServiceImpl) ListProducts(id uint) ([]model.Product, error) {
+	if _, err := s.categoryRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	tree, err := s.tree()
+	if err != nil {
+		return nil, err
+	}
+	return s.prodRepo.ListByCategories(tree.subtree(id))
+}
+
+func (s *categoryServiceImpl) UpdateCategory(id uint, input dto.CreateCategoryDTO) (*model.Category, error) {
+	c, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fill(c, input); err != nil {
+		return nil, err
+	}
+	if err := s.categoryRepo.Update(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteCategory only deletes a category without subcategories; its products are left
+// without a category.
+func (s *categoryServiceImpl) DeleteCategory(id uint) error {
+	n, err := s.categoryRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return errors.New("category has subcategories; move or delete them first")
+	}
+	return s.categoryRepo.Delete(id)
+}
+
+func (s *categoryServiceImpl) tree() (categoryTree, error) {
+	list, err := s.categoryRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	return newCategoryTree(list), nil
+}
+
+// fill copies the input onto c after checking its parent and tax class. A category cannot
+// be moved under itself or one of its own subcategories.
+func (s *categoryServiceImpl) fill(c *model.Category, input dto.CreateCategoryDTO) error {
+	if input.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(*input.ParentID); err != nil {
+			return fmt.Errorf("parent category %d not found: %w", *input.ParentID, err)
+		}
+		if c.ID != 0 {
+			tree, err := s.tree()
+			if err != nil {
+				return err
+			}
+			if tree.within(input.ParentID, c.ID) {
+				return errors.New("a category cannot be moved under itself or its subcategories")
+			}
+		}
+	}
+	if input.TaxClassID != nil {
+		if _, err := s.taxRepo.GetClass(*input.TaxClassID); err != nil {
+			return fmt.Errorf("tax class %d not found: %w", *input.TaxClassID, err)
+		}
+	}
+	c.Name = input.Name
+	c.ParentID = input.ParentID
+	c.TaxClassID = input.TaxClassID
+	return nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import "github.com/nawodahansani/pos-backend/model"
+
+// categoryTree is the whole catalog tree by category id. The tree is small, so it is read
+// in one go and walked in memory rather than queried level by level.
+type categoryTree map[uint]model.Category
+
+func newCategoryTree(list []model.Category) categoryTree {
+	t := make(categoryTree, len(list))
+	for _, c := range list {
+		t[c.ID] = c
+	}
+	return t
+}
+
+// path lists id and its ancestors, nearest first. It stops at a category it has already
+// seen, so a broken tree cannot loop.
+func (t categoryTree) path(id *uint) []model.Category {
+	var path []model.Category
+	seen := map[uint]bool{}
+	for id != nil && !seen[*id] {
+		c, ok := t[*id]
+		if !ok {
+			break
+		}
+		seen[c.ID] = true
+		path = append(path, c)
+		id = c.ParentID
+	}
+	return path
+}
+
+// within reports whether category id is ancestor or one of its subcategories.
+func (t categoryTree) within(id *uint, ancestor uint) bool {
+	for _, c := range t.path(id) {
+		if c.ID == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// taxClass is the tax class set on category id or its nearest ancestor with one.
+func (t categoryTree) taxClass(id *uint) *uint {
+	for _, c := range t.path(id) {
+		if c.TaxClassID != nil {
+			return c.TaxClassID
+		}
+	}
+	return nil
+}
+
+// subtree lists id and every category below it.
+func (t categoryTree) subtree(id uint) []uint {
+	ids := []uint{}
+	for cid := range t {
+		if t.within(&cid, id) {
+			ids = append(ids, cid)
+		}
+	}
+	return ids
+}
+
+// nestCategories builds the tree of the categories, children in the order listed.
+func nestCategories(list []model.Category) []model.Category {
+	children := map[uint][]model.Category{}
+	var roots []model.Category
+	for _, c := range list {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+	var fill func(cs []model.Category) []model.Category
+	fill = func(cs []model.Category) []model.Category {
+		for i := range cs {
+			cs[i].Children = fill(children[cs[i].ID])
+		}
+		return cs
+	}
+	return fill(roots)
+}
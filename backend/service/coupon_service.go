@@ -0,0 +1,219 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type CouponService interface {
+	CreateCoupon(input dto.CreateCouponDTO) (*model.Coupon, error)
+	GenerateCoupons(input dto.GenerateCouponsDTO) ([]model.Coupon, error)
+	GetCoupon(id uint) (*model.Coupon, error)
+	ListCoupons() ([]model.Coupon, error)
+	UpdateCoupon(id uint, input dto.CreateCouponDTO) (*model.Coupon, error)
+	DeleteCoupon(id uint) error
+}
+
+type couponServiceImpl struct {
+	db         *gorm.DB
+	couponRepo repository.CouponRepository
+}
+
+func NewCouponService(db *gorm.DB, cr repository.CouponRepository) CouponService {
+	return &couponServiceImpl{db: db, couponRepo: cr}
+}
+
+// couponAlphabet leaves out characters that are easily misread: 0/O and 1/I.
+const couponAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func generateCouponCode(prefix string) (string, error) {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(prefix))
+	max := big.NewInt(int64(len(couponAlphabet)))
+	for i := 0; i < 10; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(couponAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func fillCoupon(c *model.Coupon, input dto.CreateCouponDTO) error {
+	switch input.Type {
+	case model.DiscountPercent:
+		if input.Percent <= 0 {
+			return errors.New("a percent coupon needs a percent")
+		}
+	case model.DiscountFixed:
+		if input.Amount <= 0 {
+			return errors.New("a fixed coupon needs an amount")
+		}
+	}
+	c.Description = input.Description
+	c.Type = input.Type
+	c.Percent = input.Percent
+	c.Amount = input.Amount
+	c.MinSpend = input.MinSpend
+	c.MaxUses = input.MaxUses
+	c.PerCustomerLimit = input.PerCustomerLimit
+	c.Active = input.Active
+	c.ExpiresAt = input.ExpiresAt
+	return nil
+}
+
+func (s *couponServiceImpl) CreateCoupon(input dto.CreateCouponDTO) (*model.Coupon, error) {
+	c := model.Coupon{Code: normalizeCouponCode(input.Code)}
+	if err := fillCoupon(&c, input); err != nil {
+		return nil, err
+	}
+	if c.Code == "" {
+		code, err := generateCouponCode("")
+		if err != nil {
+			return nil, err
+		}
+		c.Code = code
+	}
+	if err := s.couponRepo.Create(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GenerateCoupons creates a batch of coupons in one transaction, so either every code is
+// created or none is.
+func (s *couponServiceImpl) GenerateCoupons(input dto.GenerateCouponsDTO) ([]model.Coupon, error) {
+	var template model.Coupon
+	if err := fillCoupon(&template, input.CreateCouponDTO); err != nil {
+		return nil, err
+	}
+
+	list := make([]model.Coupon, 0, input.Count)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txCouponRepo := impl.NewCouponRepoImpl(tx)
+		for i := 0; i < input.Count; i++ {
+			c := template
+			code, err := generateCouponCode(input.Prefix)
+			if err != nil {
+				return err
+			}
+			c.Code = code
+			if err := txCouponRepo.Create(&c); err != nil {
+				return fmt.Errorf("create coupon %s: %w", code, err)
+			}
+			list = append(list, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *couponServiceImpl) GetCoupon(id uint) (*model.Coupon, error) {
+	return s.couponRepo.GetByID(id)
+}
+
+func (s *couponServiceImpl) ListCoupons() ([]model.Coupon, error) {
+	return s.couponRepo.List()
+}
+
+// UpdateCoupon changes a coupon's settings; its code and use count are kept.
+func (s *couponServiceImpl) UpdateCoupon(id uint, input dto.CreateCouponDTO) (*model.Coupon, error) {
+	c, err := s.couponRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillCoupon(c, input); err != nil {
+		return nil, err
+	}
+	if err := s.couponRepo.Update(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *couponServiceImpl) DeleteCoupon(id uint) error {
+	return s.couponRepo.Delete(id)
+}
+
+// findCoupon looks up a code given at checkout and checks it can still be used. Returns
+// nil when no code was given.
+func findCoupon(couponRepo repository.CouponRepository, code string, at time.Time) (*model.Coupon, error) {
+	code = normalizeCouponCode(code)
+	if code == "" {
+		return nil, nil
+	}
+	c, err := couponRepo.GetByCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("coupon %s not found: %w", code, err)
+	}
+	switch {
+	case !c.Active:
+		return nil, fmt.Errorf("coupon %s is not active", code)
+	case c.ExpiresAt != nil && !at.Before(*c.ExpiresAt):
+		return nil, fmt.Errorf("coupon %s has expired", code)
+	case c.MaxUses > 0 && c.UsedCount >= c.MaxUses:
+		return nil, fmt.Errorf("coupon %s has been used up", code)
+	}
+	return c, nil
+}
+
+// couponDiscount is what coupon takes off base, the order after promotions and manual
+// discounts, which must reach its minimum spend. It never takes off more than base.
+func couponDiscount(coupon *model.Coupon, base model.Money) (model.Money, error) {
+	if base < coupon.MinSpend {
+		return 0, fmt.Errorf("coupon %s needs a minimum spend of %s", coupon.Code, coupon.MinSpend)
+	}
+	off := coupon.Amount
+	if coupon.Type == model.DiscountPercent {
+		off = coupon.Percent.Of(base)
+	}
+	if off > base {
+		off = base
+	}
+	return off, nil
+}
+
+// redeemCoupon takes a use of the order's coupon inside the order transaction. Redeem
+// locks the coupon row, so concurrent orders with the same code queue here and the
+// per-customer count below sees every earlier redemption.
+func redeemCoupon(couponRepo repository.CouponRepository, coupon *model.Coupon, order *model.Order) error {
+	if coupon == nil {
+		return nil
+	}
+	if err := couponRepo.Redeem(coupon.ID, time.Now()); err != nil {
+		return fmt.Errorf("coupon %s: %w", coupon.Code, err)
+	}
+	if coupon.PerCustomerLimit > 0 {
+		n, err := couponRepo.CountRedemptions(coupon.ID, order.CustomerID)
+		if err != nil {
+			return err
+		}
+		if n >= int64(coupon.PerCustomerLimit) {
+			return fmt.Errorf("coupon %s can only be used %d times per customer", coupon.Code, coupon.PerCustomerLimit)
+		}
+	}
+	return couponRepo.CreateRedemption(&model.CouponRedemption{
+		CouponID:   coupon.ID,
+		OrderID:    order.ID,
+		CustomerID: order.CustomerID,
+		Amount:     order.CouponDiscount,
+	})
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"gorm.io/gorm"
+)
+
+// fakeCouponRepo keeps coupons and redemptions in memory, redeeming as the database does.
+type fakeCouponRepo struct {
+	coupons     map[string]*model.Coupon
+	redemptions []model.CouponRedemption
+}
+
+func newFakeCouponRepo(coupons ...model.Coupon) *fakeCouponRepo {
+	r := &fakeCouponRepo{coupons: map[string]*model.Coupon{}}
+	for i := range coupons {
+		c := coupons[i]
+		r.coupons[c.Code] = &c
+	}
+	return r
+}
+
+func (r *fakeCouponRepo) byID(id uint) *model.Coupon {
+	for _, c := range r.coupons {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (r *fakeCouponRepo) GetByID(id uint) (*model.Coupon, error) {
+	if c := r.byID(id); c != nil {
+		return c, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeCouponRepo) GetByCode(code string) (*model.Coupon, error) {
+	if c, ok := r.coupons[code]; ok {
+		return c, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeCouponRepo) List() ([]model.Coupon, error)   { return nil, nil }
+func (r *fakeCouponRepo) Create(c *model.Coupon) error    { return nil }
+func (r *fakeCouponRepo) Update(c *model.Coupon) error    { return nil }
+func (r *fakeCouponRepo) Delete(id uint) error            { return nil }
+func (r *fakeCouponRepo) ReleaseOrder(orderID uint) error { return nil }
+
+func (r *fakeCouponRepo) Redeem(couponID uint, at time.Time) error {
+	c := r.byID(couponID)
+	if c == nil || !c.Active || (c.MaxUses > 0 && c.UsedCount >= c.MaxUses) || (c.ExpiresAt != nil && !at.Before(*c.ExpiresAt)) {
+		return errors.New("coupon is no longer valid")
+	}
+	c.UsedCount++
+	return nil
+}
+
+func (r *fakeCouponRepo) CountRedemptions(couponID, customerID uint) (int64, error) {
+	var n int64
+	for _, red := range r.redemptions {
+		if red.CouponID == couponID && red.CustomerID == customerID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeCouponRepo) CreateRedemption(red *model.CouponRedemption) error {
+	r.redemptions = append(r.redemptions, *red)
+	return nil
+}
+
+func TestCouponDiscount(t *testing.T) {
+	tests := []struct {
+		name    string
+		coupon  model.Coupon
+		base    model.Money
+		want    model.Money
+		wantErr bool
+	}{
+		{name: "fixed", coupon: model.Coupon{Type: model.DiscountFixed, Amount: 500, MinSpend: 1000}, base: 2000, want: 500},
+		{name: "minimum spend exactly reached", coupon: model.Coupon{Type: model.DiscountFixed, Amount: 500, MinSpend: 2000}, base: 2000, want: 500},
+		{name: "minimum spend not reached", coupon: model.Coupon{Type: model.DiscountFixed, Amount: 500, MinSpend: 2000}, base: 1999, wantErr: true},
+		{name: "percent", coupon: model.Coupon{Type: model.DiscountPercent, Percent: 100000}, base: 1234, want: 123},
+		{name: "percent rounds half away from zero", coupon: model.Coupon{Type: model.DiscountPercent, Percent: 100000}, base: 1235, want: 124},
+		{name: "fixed capped at the order", coupon: model.Coupon{Type: model.DiscountFixed, Amount: 5000}, base: 1200, want: 1200},
+	}
+	for _, tt := range tests {
+		got, err := couponDiscount(&tt.coupon, tt.base)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: couponDiscount = %d, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: couponDiscount = %d, %v, want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestFindCoupon(t *testing.T) {
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	later := at.Add(time.Hour)
+	repo := newFakeCouponRepo(
+		model.Coupon{ID: 1, Code: "SAVE10", Active: true, ExpiresAt: &later},
+		model.Coupon{ID: 2, Code: "OFF", Active: false},
+		model.Coupon{ID: 3, Code: "GONE", Active: true, ExpiresAt: &at},
+		model.Coupon{ID: 4, Code: "USED", Active: true, MaxUses: 1, UsedCount: 1},
+	)
+	tests := []struct {
+		code    string
+		wantID  uint
+		wantErr bool
+	}{
+		{code: "", wantID: 0},
+		{code: "   ", wantID: 0},
+		{code: " save10 ", wantID: 1},
+		{code: "OFF", wantErr: true},
+		{code: "GONE", wantErr: true},
+		{code: "USED", wantErr: true},
+		{code: "NOPE", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, err := findCoupon(repo, tt.code, at)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("findCoupon(%q) found coupon %v, want an error", tt.code, c)
+			}
+			continue
+		}
+		var id uint
+		if c != nil {
+			id = c.ID
+		}
+		if err != nil || id != tt.wantID {
+			t.Errorf("findCoupon(%q) = coupon %d, %v, want %d", tt.code, id, err, tt.wantID)
+		}
+	}
+}
+
+func TestRedeemCoupon(t *testing.T) {
+	tests := []struct {
+		name     string
+		coupon   model.Coupon
+		earlier  []model.CouponRedemption
+		customer uint
+		wantErr  bool
+	}{
+		{name: "unlimited", coupon: model.Coupon{ID: 1, Code: "A", Active: true}, customer: 7},
+		{name: "last use", coupon: model.Coupon{ID: 1, Code: "A", Active: true, MaxUses: 2, UsedCount: 1}, customer: 7},
+		{name: "used up since it was found", coupon: model.Coupon{ID: 1, Code: "A", Active: true, MaxUses: 2, UsedCount: 2}, customer: 7, wantErr: true},
+		{
+			name:     "customer limit reached",
+			coupon:   model.Coupon{ID: 1, Code: "A", Active: true, PerCustomerLimit: 1},
+			earlier:  []model.CouponRedemption{{CouponID: 1, CustomerID: 7}},
+			customer: 7,
+			wantErr:  true,
+		},
+		{
+			name:     "another customer's use does not count",
+			coupon:   model.Coupon{ID: 1, Code: "A", Active: true, PerCustomerLimit: 1},
+			earlier:  []model.CouponRedemption{{CouponID: 1, CustomerID: 8}},
+			customer: 7,
+		},
+	}
+	for _, tt := range tests {
+		repo := newFakeCouponRepo(tt.coupon)
+		repo.redemptions = append(repo.redemptions, tt.earlier...)
+		order := &model.Order{ID: 42, CustomerID: tt.customer, CouponDiscount: 250}
+		err := redeemCoupon(repo, &tt.coupon, order)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: redeemCoupon succeeded, want an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: redeemCoupon: %v", tt.name, err)
+			continue
+		}
+		last := repo.redemptions[len(repo.redemptions)-1]
+		if last.OrderID != 42 || last.CustomerID != tt.customer || last.Amount != 250 {
+			t.Errorf("%s: recorded %+v", tt.name, last)
+		}
+		if repo.coupons["A"].UsedCount != tt.coupon.UsedCount+1 {
+			t.Errorf("%s: used count %d, want %d", tt.name, repo.coupons["A"].UsedCount, tt.coupon.UsedCount+1)
+		}
+	}
+
+	if err := redeemCoupon(newFakeCouponRepo(), nil, &model.Order{}); err != nil {
+		t.Errorf("redeemCoupon without a coupon: %v", err)
+	}
+}
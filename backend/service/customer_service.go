@@ -26,9 +26,10 @@ func NewCustomerService(db *gorm.DB, cr repository.CustomerRepository) CustomerS
 
 func (s *customerServiceImpl) CreateCustomer(input dto.CreateCustomerDTO) (*model.Customer, error) {
 	c := model.Customer{
-		Name:  input.Name,
-		Email: input.Email,
-		Phone: input.Phone,
+		Name:      input.Name,
+		Email:     input.Email,
+		Phone:     input.Phone,
+		TaxExempt: input.TaxExempt,
 	}
 	if err := s.custRepo.Create(&c); err != nil {
 		return nil, err
@@ -55,6 +56,7 @@ func (s *customerServiceImpl) UpdateCustomer(id uint, input dto.CreateCustomerDT
 	customer.Name = input.Name
 	customer.Email = input.Email
 	customer.Phone = input.Phone
+	customer.TaxExempt = input.TaxExempt
 
 	if err := s.custRepo.Update(customer); err != nil {
 		return nil, err
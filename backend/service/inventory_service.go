@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type InventoryService interface {
+	AdjustStock(productID uint, input dto.StockAdjustmentDTO, userID uint) (*model.StockMovement, error)
+	ListMovements(productID uint) ([]model.StockMovement, error)
+	ListLevels(productID uint) ([]model.StockLevel, error)
+	ListLots(productID uint) ([]model.Lot, error)
+	ExpiringLots(days int, locationID uint) ([]model.Lot, error)
+	Reconcile(productID uint) ([]model.StockReconciliation, error)
+}
+
+type inventoryServiceImpl struct {
+	db           *gorm.DB
+	prodRepo     repository.ProductRepository
+	moveRepo     repository.StockMovementRepository
+	levelRepo    repository.StockLevelRepository
+	locationRepo repository.LocationRepository
+	lotRepo      repository.LotRepository
+}
+
+func NewInventoryService(db *gorm.DB, pr repository.ProductRepository, mr repository.StockMovementRepository, slr repository.StockLevelRepository, lr repository.LocationRepository, ltr repository.LotRepository) InventoryService {
+	return &inventoryServiceImpl{db: db, prodRepo: pr, moveRepo: mr, levelRepo: slr, locationRepo: lr, lotRepo: ltr}
+}
+
+// AdjustStock applies a delta to the current stock rather than setting a new value, so it
+// cannot undo a sale made at the same time. Stock may not go below zero at the location.
+// Stock of a batch-tracked product is added to the lot given, created if it is new, and
+// taken from that lot or from the lots expiring first, expired ones included. A
+// serial-tracked product's serials are named one per unit.
+func (s *inventoryServiceImpl) AdjustStock(productID uint, input dto.StockAdjustmentDTO, userID uint) (*model.StockMovement, error) {
+	switch {
+	case input.Quantity == 0:
+		return nil, fmt.Errorf("adjustment quantity must not be zero")
+	case (input.Reason == model.AdjustmentDamage || input.Reason == model.AdjustmentTheft) && input.Quantity > 0:
+		return nil, fmt.Errorf("%s can only reduce stock", input.Reason)
+	case input.Reason == model.AdjustmentFound && input.Quantity < 0:
+		return nil, fmt.Errorf("found can only add stock")
+	}
+
+	location, err := findLocation(s.locationRepo, input.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := model.StockMovement{
+		ProductID:  productID,
+		LocationID: location.ID,
+		Type:       model.StockAdjustment,
+		Quantity:   input.Quantity,
+		Reason:     input.Reason,
+		Note:       input.Note,
+		Serials:    input.Serials,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txProdRepo := impl.NewProductRepoImpl(tx)
+		p, err := txProdRepo.GetByID(productID)
+		if err != nil {
+			return err
+		}
+		ledger := newStockLedger(tx, userID)
+		if p.BatchTracked && input.Lot != nil {
+			if input.Quantity > 0 {
+				if m.LotID, err = ledger.lot(productID, location.ID, input.Lot); err != nil {
+					return err
+				}
+			} else {
+				// taking stock away never creates a lot
+				lot, err := impl.NewLotRepoImpl(tx).Find(productID, location.ID, input.Lot.Number)
+				if err != nil {
+					return fmt.Errorf("lot %s: %w", input.Lot.Number, err)
+				}
+				m.LotID = &lot.ID
+			}
+		}
+		return ledger.record(&m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (s *inventoryServiceImpl) ListMovements(productID uint) ([]model.StockMovement, error) {
+	if _, err := s.prodRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.moveRepo.ListByProduct(productID)
+}
+
+func (s *inventoryServiceImpl) ListLevels(productID uint) ([]model.StockLevel, error) {
+	if _, err := s.prodRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.levelRepo.ListByProduct(productID)
+}
+
+func (s *inventoryServiceImpl) ListLots(productID uint) ([]model.Lot, error) {
+	if _, err := s.prodRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.lotRepo.ListByProduct(productID)
+}
+
+// ExpiringLots lists the lots holding stock that expire within days, expired ones
+// included, at a location or everywhere when locationID is 0.
+func (s *inventoryServiceImpl) ExpiringLots(days int, locationID uint) ([]model.Lot, error) {
+	if days < 0 {
+		return nil, errors.New("days must not be negative")
+	}
+	if locationID != 0 {
+		if _, err := s.locationRepo.GetByID(locationID); err != nil {
+			return nil, err
+		}
+	}
+	return s.lotRepo.ListExpiring(days, locationID)
+}
+
+// Reconcile checks one product, or every product when productID is 0. A non-zero
+// difference means stock was changed outside the ledger.
+func (s *inventoryServiceImpl) Reconcile(productID uint) ([]model.StockReconciliation, error) {
+	if productID != 0 {
+		if _, err := s.prodRepo.GetByID(productID); err != nil {
+			return nil, err
+		}
+	}
+	return s.moveRepo.Reconcile(productID)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+var errKitTracked = errors.New("a kit cannot be batch-tracked or serial-tracked; its components are")
+
+// buildComponents checks a kit's bill of materials. Components are stocked products of
+// their own: not the kit, not another kit, not a product with variants and not
+// serial-tracked, since a sale names no serials for the components of a kit.
+func buildComponents(prodRepo repository.ProductRepository, kitID uint, input []dto.KitComponentDTO) ([]model.KitComponent, error) {
+	seen := map[uint]bool{}
+	components := make([]model.KitComponent, 0, len(input))
+	for _, in := range input {
+		if in.ProductID == kitID {
+			return nil, errors.New("a kit cannot be a component of itself")
+		}
+		if seen[in.ProductID] {
+			return nil, fmt.Errorf("component %d is given twice", in.ProductID)
+		}
+		seen[in.ProductID] = true
+		p, err := prodRepo.GetByID(in.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("component %d: %w", in.ProductID, err)
+		}
+		switch {
+		case p.IsKit:
+			return nil, fmt.Errorf("component %d is a kit itself", p.ID)
+		case p.HasVariants:
+			return nil, fmt.Errorf("component %d has variants: use one of them", p.ID)
+		case p.SerialTracked:
+			return nil, fmt.Errorf("component %d is serial-tracked", p.ID)
+		}
+		if err := p.CheckQuantity(in.Quantity); err != nil {
+			return nil, err
+		}
+		components = append(components, model.KitComponent{ComponentID: p.ID, Quantity: in.Quantity})
+	}
+	return components, nil
+}
+
+// fillKitStock sets the Stock of the kits among products to how many kits their
+// components in stock make, at locationID or across all locations. With a location, kits
+// none of which can be made there are dropped, as products not stocked there are.
+func fillKitStock(prodRepo repository.ProductRepository, products []model.Product, locationID uint) ([]model.Product, error) {
+	var kitIDs []uint
+	for _, p := range products {
+		if p.IsKit {
+			kitIDs = append(kitIDs, p.ID)
+		}
+	}
+	if len(kitIDs) == 0 {
+		return products, nil
+	}
+	available, err := prodRepo.KitAvailability(kitIDs, locationID)
+	if err != nil {
+		return nil, err
+	}
+	list := products[:0]
+	for _, p := range products {
+		if p.IsKit {
+			p.Stock = available[p.ID]
+			if locationID != 0 && p.Stock == 0 {
+				continue
+			}
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
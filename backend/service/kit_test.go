@@ -0,0 +1,217 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+func TestBuildComponents(t *testing.T) {
+	repo := &fakeProductRepo{products: map[uint]*model.Product{
+		1:  {ID: 1, Name: "Bun"},
+		2:  {ID: 2, Name: "Sauce", Unit: model.UnitLitre, Precision: 3},
+		3:  {ID: 3, Name: "Meal", IsKit: true},
+		4:  {ID: 4, Name: "T-shirt", HasVariants: true},
+		5:  {ID: 5, Name: "Phone", SerialTracked: true},
+		6:  {ID: 6, Name: "Cheese", BatchTracked: true},
+		10: {ID: 10, Name: "Burger kit", IsKit: true},
+	}}
+	tests := []struct {
+		name    string
+		input   []dto.KitComponentDTO
+		wantErr bool
+	}{
+		{name: "stocked components", input: []dto.KitComponentDTO{{ProductID: 1, Quantity: model.Units(2)}, {ProductID: 2, Quantity: 50}, {ProductID: 6, Quantity: model.Units(1)}}},
+		{name: "itself", input: []dto.KitComponentDTO{{ProductID: 10, Quantity: model.Units(1)}}, wantErr: true},
+		{name: "given twice", input: []dto.KitComponentDTO{{ProductID: 1, Quantity: model.Units(1)}, {ProductID: 1, Quantity: model.Units(1)}}, wantErr: true},
+		{name: "another kit", input: []dto.KitComponentDTO{{ProductID: 3, Quantity: model.Units(1)}}, wantErr: true},
+		{name: "with variants", input: []dto.KitComponentDTO{{ProductID: 4, Quantity: model.Units(1)}}, wantErr: true},
+		{name: "serial-tracked", input: []dto.KitComponentDTO{{ProductID: 5, Quantity: model.Units(1)}}, wantErr: true},
+		{name: "fraction of a whole-unit product", input: []dto.KitComponentDTO{{ProductID: 1, Quantity: 500}}, wantErr: true},
+		{name: "unknown product", input: []dto.KitComponentDTO{{ProductID: 99, Quantity: model.Units(1)}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		components, err := buildComponents(repo, 10, tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: buildComponents succeeded, want an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: buildComponents: %v", tt.name, err)
+			continue
+		}
+		if len(components) != len(tt.input) {
+			t.Errorf("%s: components = %+v", tt.name, components)
+			continue
+		}
+		for i, c := range components {
+			if c.ComponentID != tt.input[i].ProductID || c.Quantity != tt.input[i].Quantity {
+				t.Errorf("%s: component %d = %+v, want %+v", tt.name, i, c, tt.input[i])
+			}
+		}
+	}
+}
+
+// fakeKitRepo answers how many of each kit can be made.
+type fakeKitRepo struct {
+	repository.ProductRepository
+	available map[uint]model.Quantity
+}
+
+func (r *fakeKitRepo) KitAvailability(kitIDs []uint, locationID uint) (map[uint]model.Quantity, error) {
+	return r.available, nil
+}
+
+func TestFillKitStock(t *testing.T) {
+	products := func() []model.Product {
+		return []model.Product{
+			{ID: 1, Stock: model.Units(4)},
+			{ID: 10, IsKit: true, Stock: model.Units(99)},
+			{ID: 11, IsKit: true},
+		}
+	}
+	tests := []struct {
+		name     string
+		location uint
+		wantIDs  []uint
+		want     []model.Quantity
+	}{
+		{name: "everywhere", location: 0, wantIDs: []uint{1, 10, 11}, want: []model.Quantity{model.Units(4), model.Units(2), 0}},
+		{name: "at a location, kits none of which can be made there dropped", location: 1, wantIDs: []uint{1, 10}, want: []model.Quantity{model.Units(4), model.Units(2)}},
+	}
+	for _, tt := range tests {
+		repo := &fakeKitRepo{available: map[uint]model.Quantity{10: model.Units(2)}}
+		list, err := fillKitStock(repo, products(), tt.location)
+		if err != nil {
+			t.Errorf("%s: fillKitStock: %v", tt.name, err)
+			continue
+		}
+		if len(list) != len(tt.wantIDs) {
+			t.Errorf("%s: products = %+v", tt.name, list)
+			continue
+		}
+		for i, p := range list {
+			if p.ID != tt.wantIDs[i] || p.Stock != tt.want[i] {
+				t.Errorf("%s: product %d has stock %s, want product %d with %s", tt.name, p.ID, p.Stock, tt.wantIDs[i], tt.want[i])
+			}
+		}
+	}
+}
+
+// kitLedger holds 5 buns at 0.50 and 1 l of sauce at 4.00 a litre at location 1; a burger
+// kit is a bun and 50 ml of sauce.
+func kitLedger() (*stockLedger, *fakeProductRepo, *model.OrderItem) {
+	products := &fakeProductRepo{products: map[uint]*model.Product{
+		1:  {ID: 1, Name: "Bun", Stock: model.Units(5), AverageCost: 50},
+		2:  {ID: 2, Name: "Sauce", Unit: model.UnitLitre, Precision: 3, Stock: model.Units(1), AverageCost: 400},
+		10: {ID: 10, Name: "Burger kit", IsKit: true},
+	}}
+	levels := &fakeStockLevelRepo{levels: map[[2]uint]model.Quantity{
+		{1, 1}: model.Units(5),
+		{2, 1}: model.Units(1),
+	}}
+	l := &stockLedger{
+		prodRepo:  products,
+		levelRepo: levels,
+		moveRepo:  &fakeStockMovementRepo{},
+		costRepo:  &fakeCostLayerRepo{},
+		lotRepo:   &fakeLotRepo{},
+		method:    model.CostingFIFO,
+	}
+	line := &model.OrderItem{ProductID: 10, Components: []model.OrderItemComponent{
+		{ProductID: 1, Quantity: model.Units(1)},
+		{ProductID: 2, Quantity: 50},
+	}}
+	return l, products, line
+}
+
+func TestSellComponents(t *testing.T) {
+	tests := []struct {
+		kits      model.Quantity
+		wantCosts []model.Money
+		wantStock []model.Quantity
+		wantErr   bool
+	}{
+		{kits: model.Units(2), wantCosts: []model.Money{100, 40}, wantStock: []model.Quantity{model.Units(3), 900}},
+		{kits: model.Units(5), wantCosts: []model.Money{250, 100}, wantStock: []model.Quantity{0, 750}},
+		{kits: model.Units(6), wantErr: true},
+	}
+	for _, tt := range tests {
+		l, products, line := kitLedger()
+		line.Quantity = tt.kits
+		err := l.sellComponents(&model.Order{ID: 7, LocationID: 1}, line)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("selling %s kits succeeded, want an error", tt.kits)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("selling %s kits: %v", tt.kits, err)
+			continue
+		}
+		var total model.Money
+		for i, c := range line.Components {
+			if c.Cost != tt.wantCosts[i] {
+				t.Errorf("selling %s kits: component %d cost %d, want %d", tt.kits, c.ProductID, c.Cost, tt.wantCosts[i])
+			}
+			if stock := products.products[c.ProductID].Stock; stock != tt.wantStock[i] {
+				t.Errorf("selling %s kits: component %d stock %s, want %s", tt.kits, c.ProductID, stock, tt.wantStock[i])
+			}
+			total += tt.wantCosts[i]
+		}
+		if line.Cost != total {
+			t.Errorf("selling %s kits: line cost %d, want %d", tt.kits, line.Cost, total)
+		}
+		if kit := products.products[10].Stock; kit != 0 {
+			t.Errorf("selling %s kits: the kit itself holds %s", tt.kits, kit)
+		}
+	}
+}
+
+// Kits returned one at a time put their components back at the cost they were sold at,
+// so returning every kit restores stock and cost exactly.
+func TestReturnComponents(t *testing.T) {
+	l, products, line := kitLedger()
+	order := &model.Order{ID: 7, LocationID: 1}
+	line.Quantity = model.Units(3)
+	if err := l.sellComponents(order, line); err != nil {
+		t.Fatalf("sellComponents: %v", err)
+	}
+	moves := l.moveRepo.(*fakeStockMovementRepo)
+	refundID := uint(3)
+	var returned []model.Money
+	for k := model.Quantity(0); k < line.Quantity; k += model.OneUnit {
+		before := len(moves.created)
+		if err := l.returnComponents(order, *line, k, k+model.OneUnit, model.StockRefund, "refund", &refundID); err != nil {
+			t.Fatalf("returnComponents(%s, %s): %v", k, k+model.OneUnit, err)
+		}
+		for _, m := range moves.created[before:] {
+			if m.Type != model.StockRefund || m.RefType != "refund" || *m.RefID != refundID {
+				t.Errorf("return movement %+v", m)
+			}
+			returned = append(returned, m.Cost)
+		}
+	}
+	// sauce sold for 60 comes back as 20 a kit, buns for 150 as 50 each
+	want := []model.Money{50, 20, 50, 20, 50, 20}
+	if len(returned) != len(want) {
+		t.Fatalf("returned costs %v, want %v", returned, want)
+	}
+	for i := range want {
+		if returned[i] != want[i] {
+			t.Errorf("returned costs %v, want %v", returned, want)
+			break
+		}
+	}
+	if stock := products.products[1].Stock; stock != model.Units(5) {
+		t.Errorf("buns in stock %s, want 5", stock)
+	}
+	if stock := products.products[2].Stock; stock != model.Units(1) {
+		t.Errorf("sauce in stock %s, want 1", stock)
+	}
+}
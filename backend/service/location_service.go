@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type LocationService interface {
+	CreateLocation(input dto.CreateLocationDTO) (*model.Location, error)
+	List() ([]model.Location, error)
+	GetByID(id uint) (*model.Location, error)
+	UpdateLocation(id uint, input dto.CreateLocationDTO) (*model.Location, error)
+	DeleteLocation(id uint) error
+	ListStock(id uint) ([]model.StockLevel, error)
+}
+
+type locationServiceImpl struct {
+	db           *gorm.DB
+	locationRepo repository.LocationRepository
+	levelRepo    repository.StockLevelRepository
+}
+
+func NewLocationService(db *gorm.DB, lr repository.LocationRepository, slr repository.StockLevelRepository) LocationService {
+	return &locationServiceImpl{db: db, locationRepo: lr, levelRepo: slr}
+}
+
+// findLocation returns the location stock moves at: the given one, or the default
+// location when id is 0. Inactive locations cannot be used.
+func findLocation(repo repository.LocationRepository, id uint) (*model.Location, error) {
+	var l *model.Location
+	var err error
+	if id == 0 {
+		l, err = repo.GetDefault()
+	} else {
+		l, err = repo.GetByID(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("location not found: %w", err)
+	}
+	if !l.Active {
+		return nil, fmt.Errorf("location %s is not active", l.Name)
+	}
+	return l, nil
+}
+
+// save writes l and, when it is the new default, takes the flag off the old one.
+func (s *locationServiceImpl) save(l *model.Location, create bool) error {
+	if l.IsDefault && !l.Active {
+		return errors.New("the default location must be active")
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		txLocationRepo := impl.NewLocationRepoImpl(tx)
+		// only one location may be the default, so the old one is cleared first
+		if l.IsDefault {
+			if err := txLocationRepo.ClearDefault(l.ID); err != nil {
+				return err
+			}
+		}
+		if create {
+			return txLocationRepo.Create(l)
+		}
+		return txLocationRepo.Update(l)
+	})
+}
+
+func (s *locationServiceImpl) CreateLocation(input dto.CreateLocationDTO) (*model.Location, error) {
+	l := model.Location{Name: input.Name, Type: input.Type, IsDefault: input.IsDefault, Active: input.Active}
+	if err := s.save(&l, true); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (s *locationServiceImpl) List() ([]model.Location, error) {
+	return s.locationRepo.List()
+}
+
+func (s *locationServiceImpl) GetByID(id uint) (*model.Location, error) {
+	return s.locationRepo.GetByID(id)
+}
+
+// UpdateLocation cannot unset the default flag; another location has to become the default.
+func (s *locationServiceImpl) UpdateLocation(id uint, input dto.CreateLocationDTO) (*model.Location, error) {
+	l, err := s.locationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if l.IsDefault && !input.IsDefault {
+		return nil, errors.New("make another location the default instead")
+	}
+	l.Name = input.Name
+	l.Type = input.Type
+	l.IsDefault = input.IsDefault
+	l.Active = input.Active
+	if err := s.save(l, false); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// DeleteLocation refuses the default location and locations still holding stock.
+func (s *locationServiceImpl) DeleteLocation(id uint) error {
+	l, err := s.locationRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if l.IsDefault {
+		return errors.New("the default location cannot be deleted")
+	}
+	held, err := s.levelRepo.HasStock(id)
+	if err != nil {
+		return err
+	}
+	if held {
+		return fmt.Errorf("location %s still holds stock", l.Name)
+	}
+	return s.locationRepo.Delete(id)
+}
+
+func (s *locationServiceImpl) ListStock(id uint) ([]model.StockLevel, error) {
+	if _, err := s.locationRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.levelRepo.ListByLocation(id)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+type ModifierService interface {
+	CreateGroup(input dto.CreateModifierGroupDTO) (*model.ModifierGroup, error)
+	GetGroup(id uint) (*model.ModifierGroup, error)
+	ListGroups() ([]model.ModifierGroup, error)
+	UpdateGroup(id uint, input dto.CreateModifierGroupDTO) (*model.ModifierGroup, error)
+	DeleteGroup(id uint) error
+	ListByProduct(productID uint) ([]model.ModifierGroup, error)
+	SetProductGroups(productID uint, input dto.SetModifierGroupsDTO) ([]model.ModifierGroup, error)
+}
+
+type modifierServiceImpl struct {
+	modRepo  repository.ModifierRepository
+	prodRepo repository.ProductRepository
+}
+
+func NewModifierService(mr repository.ModifierRepository, pr repository.ProductRepository) ModifierService {
+	return &modifierServiceImpl{modRepo: mr, prodRepo: pr}
+}
+
+func (s *modifierServiceImpl) CreateGroup(input dto.CreateModifierGroupDTO) (*model.ModifierGroup, error) {
+	var g model.ModifierGroup
+	if err := s.fill(&g, input); err != nil {
+		return nil, err
+	}
+	if err := s.modRepo.CreateGroup(&g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (s *modifierServiceImpl) GetGroup(id uint) (*model.ModifierGroup, error) {
+	return s.modRepo.GetGroup(id)
+}
+
+func (s *modifierServiceImpl) ListGroups() ([]model.ModifierGroup, error) {
+	return s.modRepo.ListGroups()
+}
+
+func (s *modifierServiceImpl) UpdateGroup(id uint, input dto.CreateModifierGroupDTO) (*model.ModifierGroup, error) {
+	g, err := s.modRepo.GetGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fill(g, input); err != nil {
+		return nil, err
+	}
+	if err := s.modRepo.UpdateGroup(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (s *modifierServiceImpl) DeleteGroup(id uint) error {
+	return s.modRepo.DeleteGroup(id)
+}
+
+// fill checks the selection limits and ingredients and copies the input onto the group.
+// Modifiers given with an id must already be in the group.
+func (s *modifierServiceImpl) fill(g *model.ModifierGroup, input dto.CreateModifierGroupDTO) error {
+	if input.MaxSelect != 0 && input.MaxSelect < input.MinSelect {
+		return errors.New("max_select must be 0 or at least min_select")
+	}
+	if input.MinSelect > len(input.Modifiers) {
+		return fmt.Errorf("min_select is %d but the group has %d modifiers", input.MinSelect, len(input.Modifiers))
+	}
+	existing := map[uint]bool{}
+	for _, m := range g.Modifiers {
+		existing[m.ID] = true
+	}
+	modifiers := make([]model.Modifier, 0, len(input.Modifiers))
+	for _, in := range input.Modifiers {
+		if in.ID != 0 && !existing[in.ID] {
+			return fmt.Errorf("modifier %d is not in this group", in.ID)
+		}
+		if in.IngredientID != nil {
+			ingredient, err := s.prodRepo.GetByID(*in.IngredientID)
+			if err != nil {
+				return fmt.Errorf("ingredient %d: %w", *in.IngredientID, err)
+			}
+			if ingredient.HasVariants {
+				return fmt.Errorf("ingredient %d has variants: use one of them", ingredient.ID)
+			}
+			// a kit holds no stock to draw, and a serial-tracked unit cannot be picked here
+			if ingredient.IsKit {
+				return fmt.Errorf("ingredient %d is a kit: use its components", ingredient.ID)
+			}
+			if ingredient.SerialTracked {
+				return fmt.Errorf("ingredient %d is serial-tracked and cannot be an ingredient", ingredient.ID)
+			}
+			if in.IngredientQuantity <= 0 {
+				return fmt.Errorf("modifier %s: ingredient quantity must be positive", in.Name)
+			}
+			if err := ingredient.CheckQuantity(in.IngredientQuantity); err != nil {
+				return fmt.Errorf("modifier %s: %w", in.Name, err)
+			}
+		}
+		modifiers = append(modifiers, model.Modifier{
+			ID:                 in.ID,
+			GroupID:            g.ID,
+			Name:               in.Name,
+			PriceDelta:         in.PriceDelta,
+			IngredientID:       in.IngredientID,
+			IngredientQuantity: in.IngredientQuantity,
+		})
+	}
+	g.Name = input.Name
+	g.MinSelect = input.MinSelect
+	g.MaxSelect = input.MaxSelect
+	g.Modifiers = modifiers
+	return nil
+}
+
+func (s *modifierServiceImpl) ListByProduct(productID uint) ([]model.ModifierGroup, error) {
+	if _, err := s.prodRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.modRepo.ListByProduct(productID)
+}
+
+func (s *modifierServiceImpl) SetProductGroups(productID uint, input dto.SetModifierGroupsDTO) ([]model.ModifierGroup, error) {
+	if _, err := s.prodRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	groups, err := s.modRepo.GetGroups(input.GroupIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(groups))
+	for _, g := range groups {
+		found[g.ID] = true
+	}
+	for _, id := range input.GroupIDs {
+		if !found[id] {
+			return nil, fmt.Errorf("modifier group %d not found", id)
+		}
+	}
+	if err := s.modRepo.SetProductGroups(productID, groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
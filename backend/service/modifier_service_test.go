@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+func TestModifierGroupLimits(t *testing.T) {
+	three := []dto.ModifierDTO{{Name: "Cheese"}, {Name: "Bacon"}, {Name: "Egg"}}
+	tests := []struct {
+		name    string
+		min     int
+		max     int
+		wantErr bool
+	}{
+		{name: "optional", min: 0, max: 0},
+		{name: "exactly one", min: 1, max: 1},
+		{name: "all required", min: 3, max: 3},
+		{name: "minimum without a maximum", min: 2, max: 0},
+		{name: "maximum below the minimum", min: 2, max: 1, wantErr: true},
+		{name: "minimum above the modifiers", min: 4, max: 0, wantErr: true},
+	}
+	s := &modifierServiceImpl{}
+	for _, tt := range tests {
+		var g model.ModifierGroup
+		err := s.fill(&g, dto.CreateModifierGroupDTO{Name: "Extras", MinSelect: tt.min, MaxSelect: tt.max, Modifiers: three})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: fill error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (g.MinSelect != tt.min || g.MaxSelect != tt.max || len(g.Modifiers) != 3) {
+			t.Errorf("%s: group = %+v", tt.name, g)
+		}
+	}
+}
+
+// On update a modifier given with an id must already be in the group.
+func TestModifierGroupFillIDs(t *testing.T) {
+	s := &modifierServiceImpl{}
+	g := model.ModifierGroup{ID: 1, Modifiers: []model.Modifier{{ID: 3, GroupID: 1, Name: "Cheese"}}}
+	err := s.fill(&g, dto.CreateModifierGroupDTO{Name: "Extras", Modifiers: []dto.ModifierDTO{{ID: 4, Name: "Bacon"}}})
+	if err == nil {
+		t.Errorf("fill accepted a modifier of another group")
+	}
+	err = s.fill(&g, dto.CreateModifierGroupDTO{Name: "Extras", Modifiers: []dto.ModifierDTO{{ID: 3, Name: "Cheddar", PriceDelta: 80}, {Name: "Bacon"}}})
+	if err != nil {
+		t.Fatalf("fill: %v", err)
+	}
+	if len(g.Modifiers) != 2 || g.Modifiers[0].ID != 3 || g.Modifiers[0].Name != "Cheddar" || g.Modifiers[0].PriceDelta != 80 || g.Modifiers[1].GroupID != 1 {
+		t.Errorf("modifiers = %+v", g.Modifiers)
+	}
+}
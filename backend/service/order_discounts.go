@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrDiscountApprovalRequired = errors.New("discount needs manager approval")
+
+// roleRank orders roles for approvals.
+var roleRank = map[string]int{
+	model.RoleUser:    1,
+	model.RoleManager: 2,
+	model.RoleAdmin:   3,
+}
+
+// discountApprovalThreshold is the largest discount, as a share of a line's price, that may
+// be given without approval (DISCOUNT_APPROVAL_PERCENT, default 10).
+func discountApprovalThreshold() model.Percent {
+	if p, err := model.ParsePercent(os.Getenv("DISCOUNT_APPROVAL_PERCENT")); err == nil && p >= 0 {
+		return p
+	}
+	return 10 * 10000
+}
+
+// discountAmount works out what a discount takes off base. A fixed discount may not exceed it.
+func discountAmount(d *dto.DiscountDTO, base model.Money) (model.Discount, error) {
+	if d == nil {
+		return model.Discount{}, nil
+	}
+	out := model.Discount{Type: d.Type, Reason: d.Reason}
+	switch d.Type {
+	case model.DiscountPercent:
+		if d.Percent < 0 || d.Percent > model.OneHundredPercent {
+			return out, fmt.Errorf("discount of %s%% is out of range", d.Percent)
+		}
+		out.Percent = d.Percent
+		out.Amount = d.Percent.Of(base)
+	case model.DiscountFixed:
+		if d.Amount < 0 || d.Amount > base {
+			return out, fmt.Errorf("discount of %s exceeds amount %s", d.Amount, base)
+		}
+		out.Amount = d.Amount
+	default:
+		return out, fmt.Errorf("unknown discount type %q", d.Type)
+	}
+	return out, nil
+}
+
+// discountInput turns a stored discount back into input so the order can be re-priced.
+func discountInput(d model.Discount) *dto.DiscountDTO {
+	if d.Type == "" {
+		return nil
+	}
+	return &dto.DiscountDTO{Type: d.Type, Percent: d.Percent, Amount: d.Amount, Reason: d.Reason}
+}
+
+// allocate shares total out over weights in proportion. Shares are taken as differences of
+// rounded cumulative amounts, so they always add up to total.
+func allocate(total model.Money, weights []model.Money) []model.Money {
+	shares := make([]model.Money, len(weights))
+	var whole model.Money
+	for _, w := range weights {
+		whole += w
+	}
+	if whole == 0 {
+		return shares
+	}
+	var cum, prev model.Money
+	for i, w := range weights {
+		cum += w
+		next := total.MulRate(int64(cum), int64(whole))
+		shares[i] = next - prev
+		prev = next
+	}
+	return shares
+}
+
+// needsApproval reports whether any line is discounted, by its own and its share of the
+// order discount together, by more than threshold of its price.
+func (po *pricedOrder) needsApproval(threshold model.Percent) bool {
+	for _, line := range po.Lines {
+		if line.Discount.Amount+line.OrderDiscount > threshold.Of(line.Price.Mul(line.Quantity)) {
+			return true
+		}
+	}
+	return false
+}
+
+// approveDiscounts returns who approved the order's discounts, or nil when none is above
+// the threshold. Someone of a higher role than the cashier, and at least a manager, signs
+// in at the till to approve them. Admins are exempt, as no role outranks them.
+func (s *orderServiceImpl) approveDiscounts(priced *pricedOrder, cashierID uint, approval *dto.DiscountApprovalDTO) (*uint, error) {
+	threshold := discountApprovalThreshold()
+	if !priced.needsApproval(threshold) {
+		return nil, nil
+	}
+	cashier, err := s.userRepo.FindByID(cashierID)
+	if err != nil {
+		return nil, fmt.Errorf("cashier %d not found: %w", cashierID, err)
+	}
+	if cashier.Role == model.RoleAdmin {
+		return &cashierID, nil
+	}
+	if approval == nil {
+		return nil, fmt.Errorf("%w: above %s%% of the price", ErrDiscountApprovalRequired, threshold)
+	}
+
+	approver, err := s.userRepo.FindByEmail(approval.Email)
+	if err != nil || approver.ID == 0 {
+		return nil, errors.New("discount approval: invalid credentials")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(approver.Password), []byte(approval.Password)); err != nil {
+		return nil, errors.New("discount approval: invalid credentials")
+	}
+	if roleRank[approver.Role] < roleRank[model.RoleManager] {
+		return nil, fmt.Errorf("discount approval: a %s cannot approve discounts", approver.Role)
+	}
+	if roleRank[approver.Role] <= roleRank[cashier.Role] {
+		return nil, fmt.Errorf("discount approval: a %s's discounts need someone of a higher role", cashier.Role)
+	}
+	return &approver.ID, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		total   model.Money
+		weights []model.Money
+		want    []model.Money
+	}{
+		{100, []model.Money{1, 1, 1}, []model.Money{33, 34, 33}},
+		{99, []model.Money{500, 250, 250}, []model.Money{50, 24, 25}},
+		{-100, []model.Money{1, 2}, []model.Money{-33, -67}},
+		{100, []model.Money{0, 0}, []model.Money{0, 0}},
+		{1, []model.Money{1, 1, 1, 1}, []model.Money{0, 1, 0, 0}},
+	}
+	for _, tt := range tests {
+		got := allocate(tt.total, tt.weights)
+		var sum, whole model.Money
+		for i := range got {
+			sum += got[i]
+			whole += tt.weights[i]
+			if got[i] != tt.want[i] {
+				t.Errorf("allocate(%d, %v) = %v, want %v", int64(tt.total), tt.weights, got, tt.want)
+				break
+			}
+		}
+		// with no weight there is nothing to share the total over
+		if whole != 0 && sum != tt.total {
+			t.Errorf("allocate(%d, %v) shares add up to %d", int64(tt.total), tt.weights, sum)
+		}
+	}
+}
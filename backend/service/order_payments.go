@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+// applyPayments checks the tenders against the order total in the order given. Only cash
+// may exceed what is still owed; the excess is returned as change.
+func applyPayments(total model.Money, input []dto.PaymentDTO) ([]model.Payment, model.Money, error) {
+	var payments []model.Payment
+	var change model.Money
+	remaining := total
+	for _, in := range input {
+		if in.Amount <= 0 {
+			return nil, 0, fmt.Errorf("%s payment must be positive", in.Tender)
+		}
+		p := model.Payment{
+			Tender:    in.Tender,
+			Amount:    in.Amount,
+			Tendered:  in.Amount,
+			Reference: in.Reference,
+			Status:    model.PaymentCompleted,
+		}
+		if in.Amount > remaining {
+			if in.Tender != model.TenderCash {
+				return nil, 0, fmt.Errorf("%s payment of %s exceeds amount due %s", in.Tender, in.Amount, remaining)
+			}
+			p.Amount = remaining
+			p.Change = in.Amount - remaining
+			change += p.Change
+		}
+		remaining -= p.Amount
+		payments = append(payments, p)
+	}
+	if remaining > 0 {
+		return nil, 0, fmt.Errorf("payments do not cover the total: %s still due", remaining)
+	}
+	return payments, change, nil
+}
+
+// cardAuth is a hold taken on a card before the order transaction starts, so it can be
+// captured once the order is saved or released when it is not.
+type cardAuth struct {
+	index  int // position in the order's payment list
+	txnID  string
+	amount model.Money
+}
+
+func withGatewayTimeout() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), gatewayTimeout())
+}
+
+// authorizeCards places a hold for every card payment. A partial approval is refused and
+// its hold released, so the cashier can take a smaller card amount and the rest by another
+// tender.
+func (s *orderServiceImpl) authorizeCards(input []dto.PaymentDTO) ([]dto.PaymentDTO, []*cardAuth, error) {
+	payments := append([]dto.PaymentDTO(nil), input...)
+	var auths []*cardAuth
+	for i, p := range payments {
+		if p.Tender != model.TenderCard {
+			continue
+		}
+		ctx, cancel := withGatewayTimeout()
+		res, err := s.gateway.Authorize(ctx, p.Amount, p.Reference)
+		cancel()
+		if err == nil && (res.Status == GatewayDeclined || res.Amount <= 0) {
+			err = fmt.Errorf("%w: %s", ErrGatewayDeclined, res.Message)
+		}
+		if err != nil {
+			s.releaseCards(auths)
+			return nil, nil, fmt.Errorf("card payment of %s: %w", p.Amount, err)
+		}
+		if res.Amount < p.Amount {
+			s.releaseCards(append(auths, &cardAuth{txnID: res.TransactionID, amount: res.Amount}))
+			return nil, nil, fmt.Errorf("card payment of %s: only %s was approved; take that much by card and the rest by another tender", p.Amount, res.Amount)
+		}
+		auths = append(auths, &cardAuth{index: i, txnID: res.TransactionID, amount: res.Amount})
+	}
+	return payments, auths, nil
+}
+
+// attachCards records the gateway transaction on each card payment, which is saved pending
+// until captureCards settles it.
+func attachCards(payments []model.Payment, auths []*cardAuth) {
+	for _, a := range auths {
+		payments[a.index].GatewayTxnID = a.txnID
+		payments[a.index].Status = model.PaymentPending
+	}
+}
+
+// captureCards settles the holds once the order is committed, so a customer is never
+// charged for an order that was not saved, and records how each went on its payment. A
+// failed capture is logged for manual follow-up.
+func (s *orderServiceImpl) captureCards(payments []model.Payment, auths []*cardAuth) {
+	for _, a := range auths {
+		p := &payments[a.index]
+		ctx, cancel := withGatewayTimeout()
+		_, err := s.gateway.Capture(ctx, a.txnID, a.amount)
+		cancel()
+		p.Status = model.PaymentCompleted
+		if err != nil {
+			p.Status = model.PaymentFailed
+			p.Failure = err.Error()
+			log.Printf("capture card payment %s: %v", a.txnID, err)
+		}
+		if err := s.orderRepo.SetPaymentStatus(p.ID, p.Status, p.Failure); err != nil {
+			log.Printf("record payment %d as %s: %v", p.ID, p.Status, err)
+		}
+	}
+}
+
+// releaseCards voids the holds of an order that could not be saved. Failures are logged
+// for manual follow-up.
+func (s *orderServiceImpl) releaseCards(auths []*cardAuth) {
+	for _, a := range auths {
+		ctx, cancel := withGatewayTimeout()
+		_, err := s.gateway.Void(ctx, a.txnID)
+		cancel()
+		if err != nil {
+			log.Printf("release card transaction %s: %v", a.txnID, err)
+		}
+	}
+}
+
+// planRefundTenders works out how amount is paid back against the order's payments:
+// captured card payments first, the rest in the order's first other tender (cash if there
+// is none). What goes back on each card is reserved on its payment, but the card tender is
+// left pending: the gateway is only called by settleRefundTenders once the refund or void
+// is committed, so nothing that fails before then can leave the money returned.
+func planRefundTenders(orderRepo repository.OrderRepository, order *model.Order, amount model.Money) ([]model.RefundTender, error) {
+	var tenders []model.RefundTender
+	remaining := amount
+	fallback := model.TenderCash
+	fallbackSet := false
+
+	for i := range order.Payments {
+		p := &order.Payments[i]
+		if p.Tender != model.TenderCard || p.GatewayTxnID == "" {
+			if !fallbackSet {
+				fallback = p.Tender
+				fallbackSet = true
+			}
+			continue
+		}
+		refundable := p.Amount - p.RefundedAmount
+		if remaining <= 0 || refundable <= 0 {
+			continue
+		}
+		part := refundable
+		if remaining < part {
+			part = remaining
+		}
+		if err := orderRepo.AddPaymentRefund(p.ID, part); err != nil {
+			return nil, err
+		}
+		p.RefundedAmount += part
+		paymentID := p.ID
+		tenders = append(tenders, model.RefundTender{
+			OrderID:      order.ID,
+			PaymentID:    &paymentID,
+			Tender:       model.TenderCard,
+			Amount:       part,
+			GatewayTxnID: p.GatewayTxnID,
+			Status:       model.RefundTenderPending,
+		})
+		remaining -= part
+	}
+
+	if remaining > 0 {
+		tenders = append(tenders, model.RefundTender{
+			OrderID: order.ID,
+			Tender:  fallback,
+			Amount:  remaining,
+			Status:  model.RefundTenderCompleted,
+		})
+	}
+	return tenders, nil
+}
+
+// settleRefundTenders refunds the pending card tenders through the gateway after they have
+// been committed and records how each went. A failed tender keeps its amount reserved on
+// the payment, so it cannot be refunded twice; it is logged for manual follow-up.
+func (s *orderServiceImpl) settleRefundTenders(tenders []model.RefundTender) {
+	for i := range tenders {
+		t := &tenders[i]
+		if t.Status != model.RefundTenderPending {
+			continue
+		}
+		ctx, cancel := withGatewayTimeout()
+		_, err := s.gateway.Refund(ctx, t.GatewayTxnID, t.Amount)
+		cancel()
+		t.Status = model.RefundTenderCompleted
+		if err != nil {
+			t.Status = model.RefundTenderFailed
+			t.Failure = err.Error()
+			log.Printf("refund card payment %s: %v", t.GatewayTxnID, err)
+		}
+		if err := s.refundRepo.SetTenderStatus(t.ID, t.Status, t.Failure); err != nil {
+			log.Printf("record refund tender %d as %s: %v", t.ID, t.Status, err)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+// orderPricer builds priced order lines from the cart input. It reads products, tax
+// classes, categories and promotions through the repositories it is given, so it prices against the
+// current transaction, and it never touches stock. Promotions are those running at the
+// time the pricer was created.
+type orderPricer struct {
+	prodRepo  repository.ProductRepository
+	taxRepo   repository.TaxRepository
+	promoRepo repository.PromotionRepository
+	catRepo   repository.CategoryRepository
+	modRepo   repository.ModifierRepository
+	customer  *model.Customer
+	at        time.Time
+
+	taxClasses map[uint][]model.TaxRate
+	categories categoryTree
+}
+
+type pricedOrder struct {
+	Lines          []model.OrderItem
+	PromotionTotal model.Money
+	Discount       model.Discount
+	DiscountTotal  model.Money
+	Coupon         *model.Coupon
+	CouponDiscount model.Money
+	Subtotal       model.Money
+	TaxTotal       model.Money
+	Total          model.Money
+}
+
+func newOrderPricer(pr repository.ProductRepository, tr repository.TaxRepository, mr repository.PromotionRepository, cr repository.CategoryRepository, dr repository.ModifierRepository, customer *model.Customer) *orderPricer {
+	return &orderPricer{
+		prodRepo:   pr,
+		taxRepo:    tr,
+		promoRepo:  mr,
+		catRepo:    cr,
+		modRepo:    dr,
+		customer:   customer,
+		at:         time.Now(),
+		taxClasses: map[uint][]model.TaxRate{},
+	}
+}
+
+// price prices the cart. Promotions come off first, then manual line discounts; the manual
+// order discount and then the coupon are worked out on what is left and shared over the
+// lines before tax is calculated.
+func (p *orderPricer) price(items []dto.OrderItemDTO, discount *dto.DiscountDTO, coupon *model.Coupon) (*pricedOrder, error) {
+	priced := &pricedOrder{Coupon: coupon}
+	categories, err := p.catRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	p.categories = newCategoryTree(categories)
+	products := make([]*model.Product, 0, len(items))
+	for _, it := range items {
+		product, quantity, err := p.product(it)
+		if err != nil {
+			return nil, err
+		}
+		if quantity <= 0 {
+			return nil, fmt.Errorf("quantity for product %d must be positive", product.ID)
+		}
+		if err := product.CheckQuantity(quantity); err != nil {
+			return nil, err
+		}
+		if len(it.Serials) > 0 && model.Units(len(it.Serials)) != quantity {
+			return nil, fmt.Errorf("product %d: %d serial numbers given for %s units", product.ID, len(it.Serials), quantity)
+		}
+		var serials []model.OrderItemSerial
+		for _, number := range it.Serials {
+			serials = append(serials, model.OrderItemSerial{Number: number})
+		}
+		modifiers, delta, err := p.modifiers(product, it.Modifiers)
+		if err != nil {
+			return nil, err
+		}
+		if product.Price+delta < 0 {
+			return nil, fmt.Errorf("product %d: the modifiers take its price below zero", product.ID)
+		}
+		components, err := p.components(product)
+		if err != nil {
+			return nil, err
+		}
+		priced.Lines = append(priced.Lines, model.OrderItem{
+			ProductID:        product.ID,
+			Quantity:         quantity,
+			Price:            product.Price + delta,
+			PriceIncludesTax: product.PriceIncludesTax,
+			Serials:          serials,
+			Modifiers:        modifiers,
+			Components:       components,
+		})
+		products = append(products, product)
+	}
+
+	promos, err := p.promoRepo.ListActive(p.at)
+	if err != nil {
+		return nil, err
+	}
+	applyPromotions(priced.Lines, products, p.categories, promos, p.at)
+
+	bases := make([]model.Money, len(items))
+	var base model.Money
+	for i, it := range items {
+		line := &priced.Lines[i]
+		left := line.Price.Mul(line.Quantity) - line.PromotionDiscount
+		if line.Discount, err = discountAmount(it.Discount, left); err != nil {
+			return nil, fmt.Errorf("product %d: %w", line.ProductID, err)
+		}
+		bases[i] = left - line.Discount.Amount
+		base += bases[i]
+	}
+
+	orderDiscount, err := discountAmount(discount, base)
+	if err != nil {
+		return nil, fmt.Errorf("order discount: %w", err)
+	}
+	priced.Discount = orderDiscount
+	for i, share := range allocate(orderDiscount.Amount, bases) {
+		priced.Lines[i].OrderDiscount = share
+		bases[i] -= share
+	}
+	base -= orderDiscount.Amount
+
+	if coupon != nil {
+		off, err := couponDiscount(coupon, base)
+		if err != nil {
+			return nil, err
+		}
+		priced.CouponDiscount = off
+		for i, share := range allocate(off, bases) {
+			priced.Lines[i].CouponDiscount = share
+			bases[i] -= share
+		}
+	}
+
+	for i := range priced.Lines {
+		line := &priced.Lines[i]
+		if err := p.taxLine(line, products[i], bases[i]); err != nil {
+			return nil, err
+		}
+		priced.PromotionTotal += line.PromotionDiscount
+		priced.DiscountTotal += line.Discount.Amount + line.OrderDiscount
+		priced.Subtotal += line.NetAmount
+		priced.TaxTotal += line.TaxAmount
+		priced.Total += line.Total
+	}
+	return priced, nil
+}
+
+// product finds the product of a line, by barcode when no product id is given, and the
+// quantity sold: the line's, or what a scale barcode was printed for.
+func (p *orderPricer) product(it dto.OrderItemDTO) (*model.Product, model.Quantity, error) {
+	var product *model.Product
+	var err error
+	quantity := it.Quantity
+	switch {
+	case it.VariantID != 0:
+		product, err = p.prodRepo.GetByID(it.VariantID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("variant %d not found: %w", it.VariantID, err)
+		}
+		if it.ProductID != 0 && (product.ParentID == nil || *product.ParentID != it.ProductID) {
+			return nil, 0, fmt.Errorf("product %d is not a variant of product %d", it.VariantID, it.ProductID)
+		}
+	case it.ProductID == 0:
+		label, scaled := parseScaleBarcode(it.Barcode)
+		codes := barcodeCandidates(it.Barcode)
+		if scaled {
+			codes = []string{label.code}
+		}
+		product, err = p.prodRepo.GetByBarcode(codes)
+		if err != nil {
+			return nil, 0, fmt.Errorf("barcode %s not found: %w", it.Barcode, err)
+		}
+		if scaled {
+			if quantity, err = label.quantity(product); err != nil {
+				return nil, 0, err
+			}
+			if it.Quantity != 0 && it.Quantity != quantity {
+				return nil, 0, fmt.Errorf("barcode %s is for %s %s, not %s", it.Barcode, quantity, product.Unit, it.Quantity)
+			}
+		}
+	default:
+		product, err = p.prodRepo.GetByID(it.ProductID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("product %d not found: %w", it.ProductID, err)
+		}
+	}
+	if product.HasVariants {
+		return nil, 0, fmt.Errorf("product %d has variants: sell one of them", product.ID)
+	}
+	return product, quantity, nil
+}
+
+// modifiers checks the picked modifiers against the groups offered on the product and
+// returns them with what they add to the unit price. Every group offered must have its
+// number of picks within its limits, so a group with a minimum must be picked from.
+func (p *orderPricer) modifiers(product *model.Product, ids []uint) ([]model.OrderItemModifier, model.Money, error) {
+	groups, err := p.modRepo.ListByProduct(product.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	picked := map[uint]bool{}
+	for _, id := range ids {
+		if picked[id] {
+			return nil, 0, fmt.Errorf("product %d: modifier %d is picked twice", product.ID, id)
+		}
+		picked[id] = true
+	}
+	var lines []model.OrderItemModifier
+	var delta model.Money
+	for _, g := range groups {
+		n := 0
+		for _, m := range g.Modifiers {
+			if !picked[m.ID] {
+				continue
+			}
+			delete(picked, m.ID)
+			n++
+			delta += m.PriceDelta
+			lines = append(lines, model.OrderItemModifier{
+				ModifierID:         m.ID,
+				Name:               m.Name,
+				PriceDelta:         m.PriceDelta,
+				IngredientID:       m.IngredientID,
+				IngredientQuantity: m.IngredientQuantity,
+			})
+		}
+		if n < g.MinSelect {
+			return nil, 0, fmt.Errorf("product %d: pick at least %d of %s", product.ID, g.MinSelect, g.Name)
+		}
+		if g.MaxSelect != 0 && n > g.MaxSelect {
+			return nil, 0, fmt.Errorf("product %d: pick at most %d of %s", product.ID, g.MaxSelect, g.Name)
+		}
+	}
+	for _, id := range ids {
+		if picked[id] {
+			return nil, 0, fmt.Errorf("product %d: modifier %d is not offered on it", product.ID, id)
+		}
+	}
+	return lines, delta, nil
+}
+
+// components returns what a kit is made of, for the line to take from stock in its place.
+func (p *orderPricer) components(product *model.Product) ([]model.OrderItemComponent, error) {
+	if !product.IsKit {
+		return nil, nil
+	}
+	kit, err := p.prodRepo.ListComponents(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(kit) == 0 {
+		return nil, fmt.Errorf("kit %d has no components", product.ID)
+	}
+	components := make([]model.OrderItemComponent, 0, len(kit))
+	for _, c := range kit {
+		components = append(components, model.OrderItemComponent{ProductID: c.ComponentID, Quantity: c.Quantity})
+	}
+	return components, nil
+}
+
+// taxLine calculates tax on the discounted line amount and fills in the line totals.
+func (p *orderPricer) taxLine(line *model.OrderItem, product *model.Product, amount model.Money) error {
+	rates, err := p.ratesFor(product)
+	if err != nil {
+		return err
+	}
+
+	net, taxes := calculateLineTax(amount, rates, product.PriceIncludesTax)
+	if p.customer != nil && p.customer.TaxExempt {
+		taxes = nil
+	}
+	line.NetAmount = net
+	line.Taxes = taxes
+	for _, t := range taxes {
+		line.TaxAmount += t.Amount
+	}
+	line.Total = net + line.TaxAmount
+	return nil
+}
+
+// ratesFor finds the product's tax rates through its own tax class or, without one, the
+// one its category or the nearest ancestor of it has.
+func (p *orderPricer) ratesFor(product *model.Product) ([]model.TaxRate, error) {
+	classID := product.TaxClassID
+	if classID == nil {
+		classID = p.categories.taxClass(product.CategoryID)
+	}
+	if classID == nil {
+		return nil, nil
+	}
+	id := *classID
+	if rates, ok := p.taxClasses[id]; ok {
+		return rates, nil
+	}
+	class, err := p.taxRepo.GetClass(id)
+	if err != nil {
+		return nil, fmt.Errorf("tax class %d for product %d: %w", id, product.ID, err)
+	}
+	p.taxClasses[id] = class.Rates
+	return class.Rates, nil
+}
+
+// applyTo copies the priced lines and totals onto the order.
+func (po *pricedOrder) applyTo(order *model.Order) {
+	order.Items = po.Lines
+	order.PromotionTotal = po.PromotionTotal
+	order.Discount = po.Discount
+	order.DiscountTotal = po.DiscountTotal
+	order.CouponID = nil
+	order.CouponCode = ""
+	if po.Coupon != nil {
+		order.CouponID = &po.Coupon.ID
+		order.CouponCode = po.Coupon.Code
+	}
+	order.CouponDiscount = po.CouponDiscount
+	order.Subtotal = po.Subtotal
+	order.TaxTotal = po.TaxTotal
+	order.Total = po.Total
+}
+
+// refundLine takes the share of a sold line, including each of its taxes, for qty more
+// units on top of what has already been refunded from it.
+func refundLine(line model.OrderItem, qty model.Quantity) model.RefundItem {
+	before, after := line.RefundedQuantity, line.RefundedQuantity+qty
+	item := model.RefundItem{
+		OrderItemID: line.ID,
+		ProductID:   line.ProductID,
+		Quantity:    qty,
+		Amount:      prorate(line.Total, before, after, line.Quantity),
+		Cost:        prorate(line.Cost, before, after, line.Quantity),
+	}
+	// a kit's components go back one by one, each at its own share of their cost
+	if len(line.Components) > 0 {
+		item.Cost = 0
+		for _, c := range line.Components {
+			item.Cost += prorate(c.Cost, before, after, line.Quantity)
+		}
+	}
+	for _, t := range line.Taxes {
+		share := prorate(t.Amount, before, after, line.Quantity)
+		item.TaxAmount += share
+		item.Taxes = append(item.Taxes, model.RefundItemTax{
+			TaxRateID: t.TaxRateID,
+			Name:      t.Name,
+			Rate:      t.Rate,
+			Amount:    share,
+		})
+	}
+	return item
+}
+
+func serialNumbers(serials []model.OrderItemSerial) []string {
+	var numbers []string
+	for _, s := range serials {
+		numbers = append(numbers, s.Number)
+	}
+	return numbers
+}
+
+func modifierIDs(modifiers []model.OrderItemModifier) []uint {
+	var ids []uint
+	for _, m := range modifiers {
+		ids = append(ids, m.ModifierID)
+	}
+	return ids
+}
+
+// itemInputs rebuilds the cart input, line discounts, serials and modifiers included, from
+// a stored order so a parked order can be re-priced.
+func itemInputs(order *model.Order) []dto.OrderItemDTO {
+	items := make([]dto.OrderItemDTO, 0, len(order.Items))
+	for _, line := range order.Items {
+		items = append(items, dto.OrderItemDTO{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Discount:  discountInput(line.Discount),
+			Serials:   serialNumbers(line.Serials),
+			Modifiers: modifierIDs(line.Modifiers),
+		})
+	}
+	return items
+}
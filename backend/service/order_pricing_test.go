@@ -0,0 +1,105 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+// fakeModifierRepo offers groups on products by product id.
+type fakeModifierRepo struct {
+	repository.ModifierRepository
+	offered map[uint][]model.ModifierGroup
+}
+
+func (r *fakeModifierRepo) ListByProduct(productID uint) ([]model.ModifierGroup, error) {
+	return r.offered[productID], nil
+}
+
+func TestOrderPricerModifiers(t *testing.T) {
+	ingredient := uint(9)
+	coffee := []model.ModifierGroup{
+		{ID: 1, Name: "Size", MinSelect: 1, MaxSelect: 1, Modifiers: []model.Modifier{
+			{ID: 1, Name: "Small"},
+			{ID: 2, Name: "Large", PriceDelta: 150},
+		}},
+		{ID: 2, Name: "Extras", MaxSelect: 2, Modifiers: []model.Modifier{
+			{ID: 3, Name: "Cheese", PriceDelta: 75},
+			{ID: 4, Name: "Bacon", PriceDelta: 120},
+			{ID: 5, Name: "Egg", PriceDelta: 60, IngredientID: &ingredient, IngredientQuantity: 50},
+		}},
+		{ID: 3, Name: "Sauce", Modifiers: []model.Modifier{
+			{ID: 6, Name: "Ketchup"},
+			{ID: 7, Name: "Mayo", PriceDelta: -10},
+		}},
+	}
+	tests := []struct {
+		name      string
+		product   uint
+		ids       []uint
+		wantNames []string
+		wantDelta model.Money
+		wantErr   bool
+	}{
+		{name: "required group", product: 1, ids: []uint{2}, wantNames: []string{"Large"}, wantDelta: 150},
+		{name: "up to the maximum", product: 1, ids: []uint{4, 1, 3}, wantNames: []string{"Small", "Cheese", "Bacon"}, wantDelta: 195},
+		{name: "group without a maximum", product: 1, ids: []uint{2, 6, 7}, wantNames: []string{"Large", "Ketchup", "Mayo"}, wantDelta: 140},
+		{name: "below the minimum", product: 1, ids: []uint{3}, wantErr: true},
+		{name: "nothing picked", product: 1, wantErr: true},
+		{name: "above the maximum of one", product: 1, ids: []uint{1, 2}, wantErr: true},
+		{name: "above the maximum of two", product: 1, ids: []uint{1, 3, 4, 5}, wantErr: true},
+		{name: "picked twice", product: 1, ids: []uint{1, 1}, wantErr: true},
+		{name: "not offered", product: 1, ids: []uint{1, 99}, wantErr: true},
+		{name: "no groups", product: 2},
+		{name: "none offered on the product", product: 2, ids: []uint{1}, wantErr: true},
+	}
+	p := &orderPricer{modRepo: &fakeModifierRepo{offered: map[uint][]model.ModifierGroup{1: coffee}}}
+	for _, tt := range tests {
+		lines, delta, err := p.modifiers(&model.Product{ID: tt.product}, tt.ids)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: modifiers succeeded, want an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: modifiers: %v", tt.name, err)
+			continue
+		}
+		if delta != tt.wantDelta {
+			t.Errorf("%s: delta = %d, want %d", tt.name, delta, tt.wantDelta)
+		}
+		var names []string
+		for _, l := range lines {
+			names = append(names, l.Name)
+		}
+		if len(names) != len(tt.wantNames) {
+			t.Errorf("%s: modifiers = %v, want %v", tt.name, names, tt.wantNames)
+			continue
+		}
+		for i := range names {
+			if names[i] != tt.wantNames[i] {
+				t.Errorf("%s: modifiers = %v, want %v", tt.name, names, tt.wantNames)
+				break
+			}
+		}
+	}
+}
+
+// A picked modifier keeps the ingredient it uses, for the sale to take from stock.
+func TestOrderPricerModifierIngredient(t *testing.T) {
+	ingredient := uint(9)
+	p := &orderPricer{modRepo: &fakeModifierRepo{offered: map[uint][]model.ModifierGroup{1: {
+		{ID: 2, Name: "Extras", Modifiers: []model.Modifier{
+			{ID: 5, Name: "Egg", PriceDelta: 60, IngredientID: &ingredient, IngredientQuantity: 50},
+		}},
+	}}}}
+	lines, _, err := p.modifiers(&model.Product{ID: 1}, []uint{5})
+	if err != nil {
+		t.Fatalf("modifiers: %v", err)
+	}
+	if len(lines) != 1 || lines[0].ModifierID != 5 || lines[0].PriceDelta != 60 || lines[0].IngredientID == nil || *lines[0].IngredientID != 9 || lines[0].IngredientQuantity != 50 {
+		t.Errorf("modifiers = %+v", lines)
+	}
+}
@@ -1,8 +1,8 @@
 package service
 
 import (
-	"errors"
 	"fmt"
+	"time"
 
 	"github.com/nawodahansani/pos-backend/dto"
 	"github.com/nawodahansani/pos-backend/model"
@@ -12,74 +12,159 @@ import (
 )
 
 type OrderService interface {
-	CreateOrder(input dto.CreateOrderDTO) (*model.Order, error)
+	CreateOrder(input dto.CreateOrderDTO, userID uint) (*model.Order, error)
 	GetOrder(id uint) (*model.Order, error)
-	ListOrders() ([]model.Order, error)
+	ListOrders(status string) ([]model.Order, error)
+	ParkOrder(input dto.CreateOrderDTO, userID uint) (*model.Order, error)
+	UpdateParkedOrder(id uint, input dto.CreateOrderDTO, userID uint) (*model.Order, error)
+	ResumeOrder(id uint, input dto.CompleteOrderDTO, userID uint) (*model.Order, error)
+	VoidOrder(id uint, input dto.VoidOrderDTO, userID uint) (*model.Order, error)
+	RefundOrder(orderID uint, input dto.CreateRefundDTO, userID uint) (*model.Refund, error)
+	ListRefunds(orderID uint) ([]model.Refund, error)
 }
 
 type orderServiceImpl struct {
-	db        *gorm.DB
-	orderRepo repository.OrderRepository
-	prodRepo  repository.ProductRepository
-	custRepo  repository.CustomerRepository
-	// orderImpl   impl.OrderRepoImpl
-	// prodImpl    impl.ProductRepoImpl
-	// custImpl    impl.CustomerRepoImpl
+	db         *gorm.DB
+	orderRepo  repository.OrderRepository
+	prodRepo   repository.ProductRepository
+	custRepo   repository.CustomerRepository
+	refundRepo repository.RefundRepository
+	taxRepo    repository.TaxRepository
+	promoRepo  repository.PromotionRepository
+	couponRepo repository.CouponRepository
+	userRepo   repository.UserRepository
+	locRepo    repository.LocationRepository
+	catRepo    repository.CategoryRepository
+	modRepo    repository.ModifierRepository
+	gateway    PaymentGateway
 }
 
-func NewOrderService(db *gorm.DB, or repository.OrderRepository, pr repository.ProductRepository, cr repository.CustomerRepository) OrderService {
+func NewOrderService(db *gorm.DB, or repository.OrderRepository, pr repository.ProductRepository, cr repository.CustomerRepository, rr repository.RefundRepository, tr repository.TaxRepository, mr repository.PromotionRepository, cpr repository.CouponRepository, ur repository.UserRepository, lr repository.LocationRepository, ctr repository.CategoryRepository, dr repository.ModifierRepository, gw PaymentGateway) OrderService {
 	return &orderServiceImpl{
-		db:        db,
-		orderRepo: or,
-		prodRepo:  pr,
-		custRepo:  cr,
+		db:         db,
+		orderRepo:  or,
+		prodRepo:   pr,
+		custRepo:   cr,
+		refundRepo: rr,
+		taxRepo:    tr,
+		promoRepo:  mr,
+		couponRepo: cpr,
+		userRepo:   ur,
+		locRepo:    lr,
+		catRepo:    ctr,
+		modRepo:    dr,
+		gateway:    gw,
 	}
 }
 
-func (s *orderServiceImpl) CreateOrder(input dto.CreateOrderDTO) (*model.Order, error) {
+// orderTransitions lists the statuses an order in a given status may move to.
+// Voided and fully refunded orders are final.
+var orderTransitions = map[string][]string{
+	model.OrderStatusParked:            {model.OrderStatusParked, model.OrderStatusCompleted, model.OrderStatusVoided},
+	model.OrderStatusCompleted:         {model.OrderStatusVoided, model.OrderStatusPartiallyRefunded, model.OrderStatusRefunded},
+	model.OrderStatusPartiallyRefunded: {model.OrderStatusPartiallyRefunded, model.OrderStatusRefunded},
+}
+
+func canTransition(from, to string) bool {
+	for _, s := range orderTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionOrder enforces orderTransitions and moves the order to "to". The update is
+// conditional on the status that was read, so a concurrent change makes it fail.
+func transitionOrder(orderRepo repository.OrderRepository, order *model.Order, to string) error {
+	if !canTransition(order.Status, to) {
+		return fmt.Errorf("order %d is %s and cannot become %s", order.ID, order.Status, to)
+	}
+	if err := orderRepo.UpdateStatus(order.ID, []string{order.Status}, to); err != nil {
+		return err
+	}
+	order.Status = to
+	return nil
+}
+
+func (s *orderServiceImpl) CreateOrder(input dto.CreateOrderDTO, userID uint) (*model.Order, error) {
 	// validate customer exists
-	if _, err := s.custRepo.GetByID(input.CustomerID); err != nil {
+	customer, err := s.custRepo.GetByID(input.CustomerID)
+	if err != nil {
 		return nil, fmt.Errorf("customer not found: %w", err)
 	}
+	location, err := findLocation(s.locRepo, input.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// card holds are taken before the transaction so no row locks are held while waiting on the gateway
+	paymentInput, auths, err := s.authorizeCards(input.Payments)
+	if err != nil {
+		return nil, err
+	}
 
 	var createdOrder *model.Order
 
-	err := s.db.Transaction(func(tx *gorm.DB) error {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
 		// use repos backed by tx
-		//txProdRepo := repository.NewProductRepo(tx)
-		//txOrderRepo := repository.NewOrderRepo(tx)
 		txProdRepo := impl.NewProductRepoImpl(tx)
 		txOrderRepo := impl.NewOrderRepoImpl(tx)
+		txCouponRepo := impl.NewCouponRepoImpl(tx)
 
+		coupon, err := findCoupon(txCouponRepo, input.CouponCode, time.Now())
+		if err != nil {
+			return err
+		}
+		priced, err := newOrderPricer(txProdRepo, impl.NewTaxRepoImpl(tx), impl.NewPromotionRepoImpl(tx), impl.NewCategoryRepoImpl(tx), impl.NewModifierRepoImpl(tx), customer).price(input.Items, input.Discount, coupon)
+		if err != nil {
+			return err
+		}
+		approvedBy, err := s.approveDiscounts(priced, userID, input.Approval)
+		if err != nil {
+			return err
+		}
+		payments, change, err := applyPayments(priced.Total, paymentInput)
+		if err != nil {
+			return err
+		}
+		attachCards(payments, auths)
 
+		now := time.Now()
 		order := model.Order{
-			CustomerID: input.CustomerID,
+			CustomerID:         input.CustomerID,
+			LocationID:         location.ID,
+			Status:             model.OrderStatusCompleted,
+			DiscountApprovedBy: approvedBy,
+			PaidTotal:          priced.Total,
+			ChangeDue:          change,
+			CompletedAt:        &now,
+			Payments:           payments,
 		}
+		priced.applyTo(&order)
 
-		total := 0.0
-		for _, it := range input.Items {
-			p, err := txProdRepo.GetByID(it.ProductID)
-			if err != nil {
-				return fmt.Errorf("product %d not found: %w", it.ProductID, err)
+		if err := txOrderRepo.CreateOrder(&order); err != nil {
+			return err
+		}
+		if err := newStockLedger(tx, userID).sell(&order, order.Items); err != nil {
+			return err
+		}
+		for _, line := range order.Items {
+			if err := txOrderRepo.SetItemCost(line.ID, line.Cost); err != nil {
+				return err
 			}
-			if p.Stock < it.Quantity {
-				return errors.New("insufficient stock for product")
+			for _, mod := range line.Modifiers {
+				if err := txOrderRepo.SetModifierCost(mod.ID, mod.Cost); err != nil {
+					return err
+				}
 			}
-			if err := txProdRepo.ReduceStock(it.ProductID, it.Quantity); err != nil {
-				return err
+			for _, c := range line.Components {
+				if err := txOrderRepo.SetComponentCost(c.ID, c.Cost); err != nil {
+					return err
+				}
 			}
-			line := float64(it.Quantity) * p.Price
-			total += line
-			order.Items = append(order.Items, model.OrderItem{
-				ProductID: it.ProductID,
-				Quantity:  it.Quantity,
-				Price:     p.Price,
-			})
 		}
-
-		order.Total = total
-
-		if err := txOrderRepo.CreateOrder(&order); err != nil {
+		if err := redeemCoupon(txCouponRepo, coupon, &order); err != nil {
 			return err
 		}
 
@@ -88,17 +173,437 @@ func (s *orderServiceImpl) CreateOrder(input dto.CreateOrderDTO) (*model.Order,
 	})
 
 	if err != nil {
+		s.releaseCards(auths)
 		return nil, err
 	}
+	s.captureCards(createdOrder.Payments, auths)
 
 	return createdOrder, nil
 }
 
+func (s *orderServiceImpl) ParkOrder(input dto.CreateOrderDTO, userID uint) (*model.Order, error) {
+	customer, err := s.custRepo.GetByID(input.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %w", err)
+	}
+	location, err := findLocation(s.locRepo, input.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon, err := findCoupon(s.couponRepo, input.CouponCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	priced, err := newOrderPricer(s.prodRepo, s.taxRepo, s.promoRepo, s.catRepo, s.modRepo, customer).price(input.Items, input.Discount, coupon)
+	if err != nil {
+		return nil, err
+	}
+	approvedBy, err := s.approveDiscounts(priced, userID, input.Approval)
+	if err != nil {
+		return nil, err
+	}
+
+	order := model.Order{
+		CustomerID:         input.CustomerID,
+		LocationID:         location.ID,
+		Status:             model.OrderStatusParked,
+		DiscountApprovedBy: approvedBy,
+	}
+	priced.applyTo(&order)
+	if err := s.orderRepo.CreateOrder(&order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (s *orderServiceImpl) UpdateParkedOrder(id uint, input dto.CreateOrderDTO, userID uint) (*model.Order, error) {
+	customer, err := s.custRepo.GetByID(input.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %w", err)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txProdRepo := impl.NewProductRepoImpl(tx)
+		txOrderRepo := impl.NewOrderRepoImpl(tx)
+
+		order, err := txOrderRepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := transitionOrder(txOrderRepo, order, model.OrderStatusParked); err != nil {
+			return err
+		}
+
+		coupon, err := findCoupon(impl.NewCouponRepoImpl(tx), input.CouponCode, time.Now())
+		if err != nil {
+			return err
+		}
+		priced, err := newOrderPricer(txProdRepo, impl.NewTaxRepoImpl(tx), impl.NewPromotionRepoImpl(tx), impl.NewCategoryRepoImpl(tx), impl.NewModifierRepoImpl(tx), customer).price(input.Items, input.Discount, coupon)
+		if err != nil {
+			return err
+		}
+		approvedBy, err := s.approveDiscounts(priced, userID, input.Approval)
+		if err != nil {
+			return err
+		}
+		if err := txOrderRepo.ReplaceItems(order.ID, priced.Lines); err != nil {
+			return err
+		}
+
+		if input.LocationID != 0 {
+			location, err := findLocation(impl.NewLocationRepoImpl(tx), input.LocationID)
+			if err != nil {
+				return err
+			}
+			order.LocationID = location.ID
+		}
+		order.CustomerID = input.CustomerID
+		order.DiscountApprovedBy = approvedBy
+		priced.applyTo(order)
+		return txOrderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orderRepo.GetByID(id)
+}
+
+// ResumeOrder completes a parked cart: lines are re-priced at current prices, payments are
+// checked against the new total and stock is taken. An approval given when the cart was
+// parked still covers its discounts.
+func (s *orderServiceImpl) ResumeOrder(id uint, input dto.CompleteOrderDTO, userID uint) (*model.Order, error) {
+	paymentInput, auths, err := s.authorizeCards(input.Payments)
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []model.Payment
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txProdRepo := impl.NewProductRepoImpl(tx)
+		txOrderRepo := impl.NewOrderRepoImpl(tx)
+
+		order, err := txOrderRepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := transitionOrder(txOrderRepo, order, model.OrderStatusCompleted); err != nil {
+			return err
+		}
+
+		customer, err := impl.NewCustomerRepoImpl(tx).GetByID(order.CustomerID)
+		if err != nil {
+			return fmt.Errorf("customer not found: %w", err)
+		}
+		txCouponRepo := impl.NewCouponRepoImpl(tx)
+		coupon, err := findCoupon(txCouponRepo, order.CouponCode, time.Now())
+		if err != nil {
+			return err
+		}
+		priced, err := newOrderPricer(txProdRepo, impl.NewTaxRepoImpl(tx), impl.NewPromotionRepoImpl(tx), impl.NewCategoryRepoImpl(tx), impl.NewModifierRepoImpl(tx), customer).price(itemInputs(order), discountInput(order.Discount), coupon)
+		if err != nil {
+			return err
+		}
+		if order.DiscountApprovedBy == nil {
+			if order.DiscountApprovedBy, err = s.approveDiscounts(priced, userID, input.Approval); err != nil {
+				return err
+			}
+		}
+		var change model.Money
+		payments, change, err = applyPayments(priced.Total, paymentInput)
+		if err != nil {
+			return err
+		}
+		attachCards(payments, auths)
+		if err := newStockLedger(tx, userID).sell(order, priced.Lines); err != nil {
+			return err
+		}
+		if err := txOrderRepo.ReplaceItems(order.ID, priced.Lines); err != nil {
+			return err
+		}
+		if err := txOrderRepo.CreatePayments(order.ID, payments); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		priced.applyTo(order)
+		order.PaidTotal = priced.Total
+		order.ChangeDue = change
+		order.CompletedAt = &now
+		if err := txOrderRepo.Update(order); err != nil {
+			return err
+		}
+		return redeemCoupon(txCouponRepo, coupon, order)
+	})
+	if err != nil {
+		s.releaseCards(auths)
+		return nil, err
+	}
+	s.captureCards(payments, auths)
+
+	return s.orderRepo.GetByID(id)
+}
+
+// VoidOrder cancels a parked or completed order. Stock taken by a completed order is put
+// back at its selling location and its card payments are refunded once the void is saved;
+// the order itself is kept with status voided for audit.
+func (s *orderServiceImpl) VoidOrder(id uint, input dto.VoidOrderDTO, userID uint) (*model.Order, error) {
+	var tenders []model.RefundTender
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txOrderRepo := impl.NewOrderRepoImpl(tx)
+
+		order, err := txOrderRepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		wasCompleted := order.Status == model.OrderStatusCompleted
+		if err := transitionOrder(txOrderRepo, order, model.OrderStatusVoided); err != nil {
+			return err
+		}
+
+		if wasCompleted {
+			ledger := newStockLedger(tx, userID)
+			for _, line := range order.Items {
+				// what is left of the line goes back at the cost it was sold at
+				if err := restockVoided(ledger, order, line); err != nil {
+					return fmt.Errorf("restock: %w", err)
+				}
+				// ingredients of refunded units were used up; the rest go back
+				for _, mod := range line.Modifiers {
+					if mod.IngredientID == nil {
+						continue
+					}
+					lotID, err := ledger.soldLot(order.ID, *mod.IngredientID)
+					if err != nil {
+						return err
+					}
+					err = ledger.recordAt(&model.StockMovement{
+						ProductID:  *mod.IngredientID,
+						LocationID: order.LocationID,
+						Type:       model.StockVoid,
+						Quantity:   mod.IngredientQuantity.Mul(line.Quantity - line.RefundedQuantity),
+						LotID:      lotID,
+						RefType:    "order",
+						RefID:      &order.ID,
+					}, prorate(mod.Cost, line.RefundedQuantity, line.Quantity, line.Quantity))
+					if err != nil {
+						return fmt.Errorf("restock %s: %w", mod.Name, err)
+					}
+				}
+			}
+			// a voided sale does not use up its coupon
+			if err := impl.NewCouponRepoImpl(tx).ReleaseOrder(order.ID); err != nil {
+				return err
+			}
+			// card payments go back through the gateway; other tenders are handed back at the till
+			if tenders, err = planRefundTenders(txOrderRepo, order, order.PaidTotal-order.RefundedTotal); err != nil {
+				return err
+			}
+			if err := impl.NewRefundRepoImpl(tx).CreateTenders(tenders); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		order.VoidReason = input.Reason
+		order.VoidedBy = &userID
+		order.VoidedAt = &now
+		return txOrderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.settleRefundTenders(tenders)
+
+	return s.orderRepo.GetByID(id)
+}
+
+// restockVoided puts back what is left of a voided line, as the components of a kit or as
+// the product itself.
+func restockVoided(ledger *stockLedger, order *model.Order, line model.OrderItem) error {
+	if len(line.Components) > 0 {
+		return ledger.returnComponents(order, line, line.RefundedQuantity, line.Quantity, model.StockVoid, "order", &order.ID)
+	}
+	lotID, err := ledger.soldLot(order.ID, line.ProductID)
+	if err != nil {
+		return err
+	}
+	serials, err := ledger.soldSerials(order.ID, line.ProductID, serialNumbers(line.Serials))
+	if err != nil {
+		return err
+	}
+	return ledger.recordAt(&model.StockMovement{
+		ProductID:  line.ProductID,
+		LocationID: order.LocationID,
+		Type:       model.StockVoid,
+		Quantity:   line.Quantity - line.RefundedQuantity,
+		LotID:      lotID,
+		Serials:    serials,
+		RefType:    "order",
+		RefID:      &order.ID,
+	}, prorate(line.Cost, line.RefundedQuantity, line.Quantity, line.Quantity))
+}
+
 func (s *orderServiceImpl) GetOrder(id uint) (*model.Order, error) {
 	return s.orderRepo.GetByID(id)
 }
 
-func (s *orderServiceImpl) ListOrders() ([]model.Order, error) {
-	return s.orderRepo.List()
+func (s *orderServiceImpl) ListOrders(status string) ([]model.Order, error) {
+	return s.orderRepo.List(status)
 }
 
+// RefundOrder returns goods from a completed order. The refund is saved with its card
+// tenders pending and the cards are refunded only once it is committed; a card refund that
+// then fails is recorded on its tender.
+func (s *orderServiceImpl) RefundOrder(orderID uint, input dto.CreateRefundDTO, userID uint) (*model.Refund, error) {
+	var createdRefund *model.Refund
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txOrderRepo := impl.NewOrderRepoImpl(tx)
+		txRefundRepo := impl.NewRefundRepoImpl(tx)
+		txProdRepo := impl.NewProductRepoImpl(tx)
+
+		order, err := txOrderRepo.GetByID(orderID)
+		if err != nil {
+			return fmt.Errorf("order %d not found: %w", orderID, err)
+		}
+		if order.Status != model.OrderStatusCompleted && order.Status != model.OrderStatusPartiallyRefunded {
+			return fmt.Errorf("order %d is %s and cannot be refunded", orderID, order.Status)
+		}
+		lines := make(map[uint]model.OrderItem, len(order.Items))
+		for _, item := range order.Items {
+			lines[item.ID] = item
+		}
+
+		refund := model.Refund{
+			OrderID: orderID,
+			UserID:  userID,
+			Reason:  input.Reason,
+		}
+
+		ledger := newStockLedger(tx, userID)
+		var total model.Money
+		// the lines as they were before each refund item, for the kits among them
+		var returned []model.OrderItem
+		for _, it := range input.Items {
+			line, ok := lines[it.OrderItemID]
+			if !ok {
+				return fmt.Errorf("order item %d does not belong to order %d", it.OrderItemID, orderID)
+			}
+			p, err := txProdRepo.GetByID(line.ProductID)
+			if err != nil {
+				return fmt.Errorf("order item %d: %w", line.ID, err)
+			}
+			if err := p.CheckQuantity(it.Quantity); err != nil {
+				return fmt.Errorf("order item %d: %w", line.ID, err)
+			}
+			if err := checkReturnedSerials(ledger, order.ID, line, it.Serials); err != nil {
+				return fmt.Errorf("order item %d: %w", line.ID, err)
+			}
+			// guarded update: fails if this would refund more than was sold
+			if err := txOrderRepo.AddRefundedQuantity(line.ID, it.Quantity); err != nil {
+				return fmt.Errorf("order item %d: %w", line.ID, err)
+			}
+			item := refundLine(line, it.Quantity)
+			returned = append(returned, line)
+			line.RefundedQuantity += it.Quantity
+			lines[line.ID] = line
+			total += item.Amount
+			refund.TaxTotal += item.TaxAmount
+			refund.Items = append(refund.Items, item)
+		}
+
+		refund.Total = total
+
+		tenders, err := planRefundTenders(txOrderRepo, order, total)
+		if err != nil {
+			return err
+		}
+		refund.Tenders = tenders
+
+		if err := txRefundRepo.Create(&refund); err != nil {
+			return err
+		}
+		// refunded goods go back at the cost they were sold at, reversing their share of COGS
+		for i, item := range refund.Items {
+			if line := returned[i]; len(line.Components) > 0 {
+				err := ledger.returnComponents(order, line, line.RefundedQuantity, line.RefundedQuantity+item.Quantity, model.StockRefund, "refund", &refund.ID)
+				if err != nil {
+					return fmt.Errorf("restock: %w", err)
+				}
+				continue
+			}
+			lotID, err := ledger.soldLot(order.ID, item.ProductID)
+			if err != nil {
+				return err
+			}
+			err = ledger.recordAt(&model.StockMovement{
+				ProductID:  item.ProductID,
+				LocationID: order.LocationID,
+				Type:       model.StockRefund,
+				Quantity:   item.Quantity,
+				LotID:      lotID,
+				Serials:    input.Items[i].Serials,
+				RefType:    "refund",
+				RefID:      &refund.ID,
+			}, item.Cost)
+			if err != nil {
+				return fmt.Errorf("restock: %w", err)
+			}
+		}
+		if err := txOrderRepo.AddRefundedTotal(orderID, total); err != nil {
+			return err
+		}
+
+		status := model.OrderStatusRefunded
+		for _, line := range lines {
+			if line.RefundedQuantity < line.Quantity {
+				status = model.OrderStatusPartiallyRefunded
+				break
+			}
+		}
+		if err := transitionOrder(txOrderRepo, order, status); err != nil {
+			return err
+		}
+
+		createdRefund = &refund
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	s.settleRefundTenders(createdRefund.Tenders)
+
+	return createdRefund, nil
+}
+
+func (s *orderServiceImpl) ListRefunds(orderID uint) ([]model.Refund, error) {
+	if _, err := s.orderRepo.GetByID(orderID); err != nil {
+		return nil, err
+	}
+	return s.refundRepo.ListByOrder(orderID)
+}
+
+// checkReturnedSerials makes sure the serials returned were sold on the line and have not
+// been returned already. How many there must be is left to the ledger.
+func checkReturnedSerials(ledger *stockLedger, orderID uint, line model.OrderItem, numbers []string) error {
+	onLine := map[string]bool{}
+	for _, s := range line.Serials {
+		onLine[s.Number] = true
+	}
+	for _, number := range numbers {
+		if !onLine[number] {
+			return fmt.Errorf("serial %s was not sold on this line", number)
+		}
+	}
+	sold, err := ledger.soldSerials(orderID, line.ProductID, numbers)
+	if err != nil {
+		return err
+	}
+	if len(sold) != len(numbers) {
+		return fmt.Errorf("%d of the serials have already been returned", len(numbers)-len(sold))
+	}
+	return nil
+}
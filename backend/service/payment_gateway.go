@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+// Gateway result statuses.
+const (
+	GatewayApproved          = "approved"
+	GatewayPartiallyApproved = "partially_approved"
+	GatewayDeclined          = "declined"
+)
+
+var (
+	ErrGatewayTimeout  = errors.New("payment gateway timed out")
+	ErrGatewayDeclined = errors.New("payment declined")
+)
+
+type GatewayResult struct {
+	TransactionID string
+	Status        string
+	// Amount is what the processor approved, which may be less than requested.
+	Amount  model.Money
+	Message string
+}
+
+// PaymentGateway is the card processor used by the order flow. Authorize places a hold,
+// Capture settles it, Void releases an uncaptured hold and Refund returns captured money.
+type PaymentGateway interface {
+	Authorize(ctx context.Context, amount model.Money, reference string) (*GatewayResult, error)
+	Capture(ctx context.Context, transactionID string, amount model.Money) (*GatewayResult, error)
+	Void(ctx context.Context, transactionID string) (*GatewayResult, error)
+	Refund(ctx context.Context, transactionID string, amount model.Money) (*GatewayResult, error)
+}
+
+// NewPaymentGatewayFromEnv builds the configured gateway. Only the simulator exists today;
+// PAYMENT_GATEWAY_MODE and PAYMENT_GATEWAY_PARTIAL_PERCENT control its behaviour, and an
+// unknown mode is an error rather than a silent approve. The simulator keeps its
+// transactions in memory, so card payments taken before a restart can no longer be
+// refunded or voided through it.
+func NewPaymentGatewayFromEnv() (PaymentGateway, error) {
+	mode := os.Getenv("PAYMENT_GATEWAY_MODE")
+	switch mode {
+	case "":
+		mode = SimulatorApprove
+	case SimulatorApprove, SimulatorDecline, SimulatorTimeout, SimulatorPartial:
+	default:
+		return nil, fmt.Errorf("unknown PAYMENT_GATEWAY_MODE %q", mode)
+	}
+	percent, err := strconv.Atoi(os.Getenv("PAYMENT_GATEWAY_PARTIAL_PERCENT"))
+	if err != nil || percent <= 0 || percent >= 100 {
+		percent = 50
+	}
+	return NewSimulatorGateway(mode, percent), nil
+}
+
+// gatewayTimeout bounds each call to the gateway (PAYMENT_GATEWAY_TIMEOUT_SECONDS, default 30).
+func gatewayTimeout() time.Duration {
+	if secs, err := strconv.Atoi(os.Getenv("PAYMENT_GATEWAY_TIMEOUT_SECONDS")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 30 * time.Second
+}
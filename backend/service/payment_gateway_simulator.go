@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+// Simulator modes.
+const (
+	SimulatorApprove = "approve"
+	SimulatorDecline = "decline"
+	SimulatorTimeout = "timeout"
+	SimulatorPartial = "partial"
+)
+
+type simTransaction struct {
+	authorized model.Money
+	captured   model.Money
+	refunded   model.Money
+	voided     bool
+}
+
+// simulatorGateway is an in-process PaymentGateway for local development. It keeps
+// transactions in memory and enforces the same state rules a real processor would.
+type simulatorGateway struct {
+	mode           string
+	partialPercent int64
+
+	mu     sync.Mutex
+	nextID int
+	txns   map[string]*simTransaction
+}
+
+func NewSimulatorGateway(mode string, partialPercent int) PaymentGateway {
+	return &simulatorGateway{
+		mode:           mode,
+		partialPercent: int64(partialPercent),
+		txns:           map[string]*simTransaction{},
+	}
+}
+
+// wait simulates a processor that never answers; it returns once the caller gives up.
+func (g *simulatorGateway) wait(ctx context.Context) error {
+	if g.mode == SimulatorTimeout {
+		<-ctx.Done()
+		return ErrGatewayTimeout
+	}
+	return nil
+}
+
+func (g *simulatorGateway) Authorize(ctx context.Context, amount model.Money, reference string) (*GatewayResult, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("authorize amount must be positive")
+	}
+	if g.mode == SimulatorDecline {
+		return &GatewayResult{Status: GatewayDeclined, Message: "declined by simulator"}, nil
+	}
+
+	approved := amount
+	status := GatewayApproved
+	if g.mode == SimulatorPartial {
+		approved = amount.MulRate(g.partialPercent, 100)
+		status = GatewayPartiallyApproved
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nextID++
+	id := fmt.Sprintf("sim_%06d", g.nextID)
+	g.txns[id] = &simTransaction{authorized: approved}
+	return &GatewayResult{TransactionID: id, Status: status, Amount: approved, Message: reference}, nil
+}
+
+func (g *simulatorGateway) Capture(ctx context.Context, transactionID string, amount model.Money) (*GatewayResult, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t, ok := g.txns[transactionID]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("unknown transaction %s", transactionID)
+	case t.voided:
+		return nil, fmt.Errorf("transaction %s is voided", transactionID)
+	case t.captured > 0:
+		return nil, fmt.Errorf("transaction %s is already captured", transactionID)
+	case amount > t.authorized:
+		return nil, fmt.Errorf("capture of %s exceeds authorized %s", amount, t.authorized)
+	}
+	t.captured = amount
+	return &GatewayResult{TransactionID: transactionID, Status: GatewayApproved, Amount: amount}, nil
+}
+
+func (g *simulatorGateway) Void(ctx context.Context, transactionID string) (*GatewayResult, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t, ok := g.txns[transactionID]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("unknown transaction %s", transactionID)
+	case t.captured > 0:
+		return nil, fmt.Errorf("transaction %s is captured; refund it instead", transactionID)
+	}
+	t.voided = true
+	return &GatewayResult{TransactionID: transactionID, Status: GatewayApproved, Amount: t.authorized}, nil
+}
+
+func (g *simulatorGateway) Refund(ctx context.Context, transactionID string, amount model.Money) (*GatewayResult, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t, ok := g.txns[transactionID]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("unknown transaction %s", transactionID)
+	case amount <= 0:
+		return nil, fmt.Errorf("refund amount must be positive")
+	case t.refunded+amount > t.captured:
+		return nil, fmt.Errorf("refund of %s exceeds refundable %s", amount, t.captured-t.refunded)
+	}
+	t.refunded += amount
+	return &GatewayResult{TransactionID: transactionID, Status: GatewayApproved, Amount: amount}, nil
+}
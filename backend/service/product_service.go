@@ -1,64 +1,260 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/nawodahansani/pos-backend/dto"
 	"github.com/nawodahansani/pos-backend/model"
 	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
 	"gorm.io/gorm"
 )
 
 type ProductService interface {
-	CreateProduct(input dto.CreateProductDTO) (*model.Product, error)
-	List() ([]model.Product, error)
+	CreateProduct(input dto.CreateProductDTO, userID uint) (*model.Product, error)
+	List(locationID uint) ([]model.Product, error)
 	GetByID(id uint) (*model.Product, error)
-	UpdateProduct(id uint, input dto.CreateProductDTO) (*model.Product, error)  // added
+	GetByBarcode(code string) (*model.Product, error)
+	UpdateProduct(id uint, input dto.UpdateProductDTO) (*model.Product, error)
+	GenerateVariants(id uint, input dto.GenerateVariantsDTO) (*model.Product, error)
+	ListVariants(id uint) ([]model.Product, error)
 	DeleteProduct(id uint) error  
 }
 
+var errBatchAndSerial = errors.New("a product cannot be both batch-tracked and serial-tracked")
+
 type productServiceImpl struct {
-	db       *gorm.DB
-	prodRepo repository.ProductRepository
+	db           *gorm.DB
+	prodRepo     repository.ProductRepository
+	locationRepo repository.LocationRepository
 }
 
-func NewProductService(db *gorm.DB, pr repository.ProductRepository) ProductService {
-	return &productServiceImpl{db: db, prodRepo: pr}
+func NewProductService(db *gorm.DB, pr repository.ProductRepository, lr repository.LocationRepository) ProductService {
+	return &productServiceImpl{db: db, prodRepo: pr, locationRepo: lr}
 }
 
-func (s *productServiceImpl) CreateProduct(input dto.CreateProductDTO) (*model.Product, error) {
+// CreateProduct records the starting stock as an opening movement in the ledger, at the
+// given location or the default one, costed at the given unit cost. The opening stock of a
+// batch-tracked product goes into the lot given with it.
+func (s *productServiceImpl) CreateProduct(input dto.CreateProductDTO, userID uint) (*model.Product, error) {
+	if input.BatchTracked && input.SerialTracked {
+		return nil, errBatchAndSerial
+	}
+	barcodes, err := buildBarcodes(s.prodRepo, 0, input.SKU, input.Barcodes)
+	if err != nil {
+		return nil, err
+	}
+	components, err := buildComponents(s.prodRepo, 0, input.Components)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) > 0 {
+		if input.Stock != 0 {
+			return nil, errors.New("a kit has no stock of its own; stock its components instead")
+		}
+		if input.BatchTracked || input.SerialTracked {
+			return nil, errKitTracked
+		}
+	}
+	location, err := findLocation(s.locationRepo, input.LocationID)
+	if err != nil {
+		return nil, err
+	}
 	p := model.Product{
-		Name:  input.Name,
-		Price: input.Price,
-		Stock: input.Stock,
+		Name:             input.Name,
+		SKU:              input.SKU,
+		Barcodes:         barcodes,
+		CategoryID:       input.CategoryID,
+		Price:            input.Price,
+		PriceIncludesTax: input.PriceIncludesTax,
+		TaxClassID:       input.TaxClassID,
+		ReorderPoint:     input.ReorderPoint,
+		ReorderQuantity:  input.ReorderQuantity,
+		SupplierID:       input.SupplierID,
+		BatchTracked:     input.BatchTracked,
+		SerialTracked:    input.SerialTracked,
+		IsKit:            len(components) > 0,
+		Components:       components,
 	}
-	if err := s.prodRepo.Create(&p); err != nil {
+	if err := setUnit(&p, input.UnitDTO, false); err != nil {
+		return nil, err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txProdRepo := impl.NewProductRepoImpl(tx)
+		if err := txProdRepo.Create(&p); err != nil {
+			return err
+		}
+		ledger := newStockLedger(tx, userID)
+		var lotID *uint
+		if input.Stock > 0 {
+			id, err := ledger.lot(p.ID, location.ID, input.Lot)
+			if err != nil {
+				return err
+			}
+			lotID = id
+		}
+		err := ledger.recordAt(&model.StockMovement{
+			ProductID:  p.ID,
+			LocationID: location.ID,
+			Type:       model.StockOpening,
+			Quantity:   input.Stock,
+			LotID:      lotID,
+			Serials:    input.Serials,
+			RefType:    "product",
+			RefID:      &p.ID,
+		}, input.UnitCost.Mul(input.Stock))
+		if err != nil {
+			return err
+		}
+		p.Stock = input.Stock
+		if input.Stock > 0 {
+			p.AverageCost = input.UnitCost
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (s *productServiceImpl) List() ([]model.Product, error) {
-	return s.prodRepo.List()
+func (s *productServiceImpl) List(locationID uint) ([]model.Product, error) {
+	products, err := s.prodRepo.List(locationID)
+	if err != nil {
+		return nil, err
+	}
+	return fillKitStock(s.prodRepo, products, locationID)
 }
 
+// GetByID returns a product with variants together with its options and variants, and a
+// kit with its components and the number of kits they make.
 func (s *productServiceImpl) GetByID(id uint) (*model.Product, error) {
-	return s.prodRepo.GetByID(id)
-}
-
-func (s *productServiceImpl) UpdateProduct(id uint, input dto.CreateProductDTO) (*model.Product, error) {
-	product, err := s.prodRepo.GetByID(id)
+	p, err := s.prodRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if p.HasVariants {
+		if p.Options, err = s.prodRepo.ListOptions(id); err != nil {
+			return nil, err
+		}
+		if p.Variants, err = s.prodRepo.ListVariants(id); err != nil {
+			return nil, err
+		}
+	}
+	if p.IsKit {
+		if p.Components, err = s.prodRepo.ListComponents(id); err != nil {
+			return nil, err
+		}
+		available, err := s.prodRepo.KitAvailability([]uint{id}, 0)
+		if err != nil {
+			return nil, err
+		}
+		p.Stock = available[id]
+	}
+	return p, nil
+}
+
+// GetByBarcode looks a scanned code up, reading a UPC-A code and the EAN-13 code with a
+// leading zero as the same barcode, and a label printed by a scale by its scale code.
+func (s *productServiceImpl) GetByBarcode(code string) (*model.Product, error) {
+	codes := barcodeCandidates(code)
+	if label, ok := parseScaleBarcode(code); ok {
+		codes = []string{label.code}
+	}
+	p, err := s.prodRepo.GetByBarcode(codes)
+	if err != nil || !p.IsKit {
+		return p, err
+	}
+	return s.GetByID(p.ID)
+}
+
+func (s *productServiceImpl) UpdateProduct(id uint, input dto.UpdateProductDTO) (*model.Product, error) {
+	// stock on hand is either all in lots or serials or in none
+	if input.BatchTracked && input.SerialTracked {
+		return nil, errBatchAndSerial
+	}
+
+	// the checks below depend on the product's stock, so they are made on the locked row
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txProdRepo := impl.NewProductRepoImpl(tx)
+		product, err := txProdRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
 
-	product.Name = input.Name
-	product.Price = input.Price
-	product.Stock = input.Stock
+		barcodes, err := buildBarcodes(txProdRepo, id, input.SKU, input.Barcodes)
+		if err != nil {
+			return err
+		}
 
-	if err := s.prodRepo.Update(product); err != nil {
+		product.Name = input.Name
+		product.SKU = input.SKU
+		product.CategoryID = input.CategoryID
+		product.Price = input.Price
+		product.PriceIncludesTax = input.PriceIncludesTax
+		product.TaxClassID = input.TaxClassID
+		product.ReorderPoint = input.ReorderPoint
+		product.ReorderQuantity = input.ReorderQuantity
+		product.SupplierID = input.SupplierID
+		if (input.BatchTracked != product.BatchTracked || input.SerialTracked != product.SerialTracked) && product.Stock != 0 {
+			return errors.New("batch and serial tracking can only be changed while the product has no stock")
+		}
+		product.BatchTracked = input.BatchTracked
+		product.SerialTracked = input.SerialTracked
+		components, err := buildComponents(txProdRepo, id, input.Components)
+		if err != nil {
+			return err
+		}
+		isKit := len(components) > 0
+		if isKit != product.IsKit && product.Stock != 0 {
+			return errors.New("a product can only become a kit, or stop being one, while it has no stock")
+		}
+		if isKit && (product.BatchTracked || product.SerialTracked) {
+			return errKitTracked
+		}
+		if isKit && product.HasVariants {
+			return errors.New("a product with variants cannot be a kit; its variants can")
+		}
+		product.IsKit = isKit
+		unit := input.UnitDTO
+		// a variant is priced on its own only once its price differs from the parent's
+		if product.ParentID != nil {
+			parent, err := txProdRepo.GetByID(*product.ParentID)
+			if err != nil {
+				return err
+			}
+			product.PriceOverride = input.Price != parent.Price
+			product.CategoryID = parent.CategoryID
+			product.TaxClassID = parent.TaxClassID
+			product.PriceIncludesTax = parent.PriceIncludesTax
+			unit = dto.UnitDTO{Unit: parent.Unit, Precision: parent.Precision, PurchaseUnit: parent.PurchaseUnit, PackSize: parent.PackSize}
+		}
+		hasStock, err := holdsStock(txProdRepo, product)
+		if err != nil {
+			return err
+		}
+		if err := setUnit(product, unit, hasStock); err != nil {
+			return err
+		}
+
+		if err := txProdRepo.Update(product); err != nil {
+			return err
+		}
+		if product.HasVariants {
+			if err := txProdRepo.SyncVariants(product); err != nil {
+				return err
+			}
+		}
+		if err := txProdRepo.ReplaceComponents(product.ID, components); err != nil {
+			return err
+		}
+		return txProdRepo.ReplaceBarcodes(product.ID, barcodes)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return product, nil
+	return s.GetByID(id)
 }
 
 func (s *productServiceImpl) DeleteProduct(id uint) error {
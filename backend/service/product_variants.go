@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	"github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+// GenerateVariants adds the given options and values to a product and makes a variant for
+// each combination of values without one. A variant starts with no stock and takes the
+// product's price, category, tax class, supplier and tracking; its SKU is the product's
+// with the values appended. A product that is a variant itself or a kit, or that still
+// holds stock, cannot get variants, nor can more than maxVariants be made.
+func (s *productServiceImpl) GenerateVariants(id uint, input dto.GenerateVariantsDTO) (*model.Product, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txProdRepo := impl.NewProductRepoImpl(tx)
+		// locked so no stock arrives between the check below and the variants being made
+		parent, err := txProdRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if parent.ParentID != nil {
+			return fmt.Errorf("product %d is a variant and cannot have variants of its own", id)
+		}
+		if parent.IsKit {
+			return fmt.Errorf("product %d is a kit and cannot have variants", id)
+		}
+		if !parent.HasVariants && parent.Stock != 0 {
+			return fmt.Errorf("product %d still has stock; variants hold the stock of a product with variants", id)
+		}
+		hadVariants := parent.HasVariants
+		parent.HasVariants = true
+		if err := txProdRepo.Update(parent); err != nil {
+			return err
+		}
+
+		options, err := addOptions(txProdRepo, id, input.Options, hadVariants)
+		if err != nil {
+			return err
+		}
+		variants, err := txProdRepo.ListVariants(id)
+		if err != nil {
+			return err
+		}
+		made := map[string]bool{}
+		for _, v := range variants {
+			made[variantKey(v.OptionValues)] = true
+		}
+		combos, err := combinations(options)
+		if err != nil {
+			return err
+		}
+		for _, combo := range combos {
+			if made[variantKey(combo)] {
+				continue
+			}
+			if err := createVariant(txProdRepo, parent, combo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// addOptions finds or creates each option and its values, matching names case-insensitively,
+// and returns all of the product's options with all of their values.
+func addOptions(prodRepo repository.ProductRepository, productID uint, input []dto.ProductOptionDTO, hasVariants bool) ([]model.ProductOption, error) {
+	options, err := prodRepo.ListOptions(productID)
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]int{}
+	for i, o := range options {
+		byName[strings.ToLower(o.Name)] = i
+	}
+	given := map[string]bool{}
+	for _, in := range input {
+		name := strings.TrimSpace(in.Name)
+		if name == "" {
+			return nil, errors.New("an option needs a name")
+		}
+		key := strings.ToLower(name)
+		if given[key] {
+			return nil, fmt.Errorf("option %s is given twice", name)
+		}
+		given[key] = true
+		i, ok := byName[key]
+		if !ok {
+			// the existing variants would have no value for a new option
+			if hasVariants {
+				return nil, fmt.Errorf("product %d already has variants; option %s cannot be added", productID, name)
+			}
+			o := model.ProductOption{ProductID: productID, Name: name, Position: len(options)}
+			if err := prodRepo.CreateOption(&o); err != nil {
+				return nil, err
+			}
+			options = append(options, o)
+			i = len(options) - 1
+			byName[key] = i
+		}
+		o := &options[i]
+		for _, value := range in.Values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return nil, fmt.Errorf("option %s has a blank value", name)
+			}
+			if hasValue(o.Values, value) {
+				continue
+			}
+			v := model.ProductOptionValue{OptionID: o.ID, Value: value, Position: len(o.Values)}
+			if err := prodRepo.CreateOptionValue(&v); err != nil {
+				return nil, err
+			}
+			o.Values = append(o.Values, v)
+		}
+	}
+	if len(given) != len(options) {
+		return nil, errors.New("every option of a product with variants must be given")
+	}
+	return options, nil
+}
+
+func hasValue(values []model.ProductOptionValue, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxVariants caps the variants of one product, which a few options with many values
+// would otherwise multiply into more than a catalogue can hold.
+const maxVariants = 500
+
+// combinations returns every combination of one value of each option, in option order,
+// failing if there are more than maxVariants.
+func combinations(options []model.ProductOption) ([][]model.ProductOptionValue, error) {
+	total := 1
+	for _, o := range options {
+		total *= len(o.Values)
+		if total > maxVariants {
+			return nil, fmt.Errorf("the options make more than %d variants", maxVariants)
+		}
+	}
+	combos := [][]model.ProductOptionValue{nil}
+	for _, o := range options {
+		var next [][]model.ProductOptionValue
+		for _, combo := range combos {
+			for _, v := range o.Values {
+				c := append(append([]model.ProductOptionValue{}, combo...), v)
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	return combos, nil
+}
+
+// variantKey identifies a combination of option values regardless of their order.
+func variantKey(values []model.ProductOptionValue) string {
+	ids := make([]int, 0, len(values))
+	for _, v := range values {
+		ids = append(ids, int(v.ID))
+	}
+	sort.Ints(ids)
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.Itoa(id))
+	}
+	return strings.Join(parts, ",")
+}
+
+func createVariant(prodRepo repository.ProductRepository, parent *model.Product, combo []model.ProductOptionValue) error {
+	names := make([]string, 0, len(combo))
+	skuParts := []string{parent.SKU}
+	for _, v := range combo {
+		names = append(names, v.Value)
+		skuParts = append(skuParts, strings.ToUpper(strings.Join(strings.Fields(v.Value), "")))
+	}
+	var sku string
+	if parent.SKU != "" {
+		sku = strings.Join(skuParts, "-")
+		if _, err := buildBarcodes(prodRepo, 0, sku, nil); err != nil {
+			return err
+		}
+	}
+	variant := model.Product{
+		Name:             parent.Name + " - " + strings.Join(names, " / "),
+		SKU:              sku,
+		CategoryID:       parent.CategoryID,
+		Price:            parent.Price,
+		PriceIncludesTax: parent.PriceIncludesTax,
+		TaxClassID:       parent.TaxClassID,
+		ReorderPoint:     parent.ReorderPoint,
+		ReorderQuantity:  parent.ReorderQuantity,
+		SupplierID:       parent.SupplierID,
+		BatchTracked:     parent.BatchTracked,
+		SerialTracked:    parent.SerialTracked,
+		Unit:             parent.Unit,
+		Precision:        parent.Precision,
+		PurchaseUnit:     parent.PurchaseUnit,
+		PackSize:         parent.PackSize,
+		ParentID:         &parent.ID,
+		OptionValues:     combo,
+	}
+	return prodRepo.Create(&variant)
+}
+
+func (s *productServiceImpl) ListVariants(id uint) ([]model.Product, error) {
+	if _, err := s.prodRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.prodRepo.ListVariants(id)
+}
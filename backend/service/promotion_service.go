@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+type PromotionService interface {
+	CreatePromotion(input dto.CreatePromotionDTO) (*model.Promotion, error)
+	GetPromotion(id uint) (*model.Promotion, error)
+	ListPromotions() ([]model.Promotion, error)
+	UpdatePromotion(id uint, input dto.CreatePromotionDTO) (*model.Promotion, error)
+	DeletePromotion(id uint) error
+}
+
+type promotionServiceImpl struct {
+	promoRepo    repository.PromotionRepository
+	prodRepo     repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewPromotionService(mr repository.PromotionRepository, pr repository.ProductRepository, cr repository.CategoryRepository) PromotionService {
+	return &promotionServiceImpl{promoRepo: mr, prodRepo: pr, categoryRepo: cr}
+}
+
+// validatePromotion checks that the fields the promotion type relies on are set.
+func validatePromotion(input dto.CreatePromotionDTO) error {
+	switch input.Type {
+	case model.PromotionBuyXGetY:
+		if input.BuyQuantity <= 0 || input.GetQuantity <= 0 {
+			return errors.New("buy_x_get_y needs buy_quantity and get_quantity")
+		}
+	case model.PromotionBundle:
+		if input.BundleQuantity < 2 || input.BundlePrice <= 0 {
+			return errors.New("bundle needs a bundle_quantity of at least 2 and a bundle_price")
+		}
+	case model.PromotionSpendThreshold:
+		if input.MinSpend <= 0 || (input.Percent > 0) == (input.Amount > 0) {
+			return errors.New("spend_threshold needs min_spend and either percent or amount")
+		}
+	case model.PromotionSale:
+		if input.Percent <= 0 {
+			return errors.New("sale needs a percent")
+		}
+	}
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if (input.DailyFrom == "") != (input.DailyTo == "") || (input.DailyFrom != "" && input.DailyFrom == input.DailyTo) {
+		return errors.New("daily_from and daily_to must both be set to different times")
+	}
+	return nil
+}
+
+// fill copies the input onto p after checking it and loading its category and products.
+func (s *promotionServiceImpl) fill(p *model.Promotion, input dto.CreatePromotionDTO) error {
+	if err := validatePromotion(input); err != nil {
+		return err
+	}
+	if input.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(*input.CategoryID); err != nil {
+			return fmt.Errorf("category %d not found: %w", *input.CategoryID, err)
+		}
+	}
+	products, err := s.prodRepo.GetByIDs(input.ProductIDs)
+	if err != nil {
+		return err
+	}
+	found := make(map[uint]bool, len(products))
+	for _, pr := range products {
+		found[pr.ID] = true
+	}
+	for _, id := range input.ProductIDs {
+		if !found[id] {
+			return fmt.Errorf("product %d not found", id)
+		}
+	}
+
+	p.Name = input.Name
+	p.Type = input.Type
+	p.Active = input.Active
+	p.Priority = input.Priority
+	p.StartsAt = input.StartsAt
+	p.EndsAt = input.EndsAt
+	p.DailyFrom = input.DailyFrom
+	p.DailyTo = input.DailyTo
+	p.BuyQuantity = input.BuyQuantity
+	p.GetQuantity = input.GetQuantity
+	p.BundleQuantity = input.BundleQuantity
+	p.BundlePrice = input.BundlePrice
+	p.MinSpend = input.MinSpend
+	p.Percent = input.Percent
+	p.Amount = input.Amount
+	p.CategoryID = input.CategoryID
+	p.Products = products
+	return nil
+}
+
+func (s *promotionServiceImpl) CreatePromotion(input dto.CreatePromotionDTO) (*model.Promotion, error) {
+	var p model.Promotion
+	if err := s.fill(&p, input); err != nil {
+		return nil, err
+	}
+	if err := s.promoRepo.Create(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *promotionServiceImpl) GetPromotion(id uint) (*model.Promotion, error) {
+	return s.promoRepo.GetByID(id)
+}
+
+func (s *promotionServiceImpl) ListPromotions() ([]model.Promotion, error) {
+	return s.promoRepo.List()
+}
+
+func (s *promotionServiceImpl) UpdatePromotion(id uint, input dto.CreatePromotionDTO) (*model.Promotion, error) {
+	p, err := s.promoRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fill(p, input); err != nil {
+		return nil, err
+	}
+	if err := s.promoRepo.Update(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *promotionServiceImpl) DeletePromotion(id uint) error {
+	return s.promoRepo.Delete(id)
+}
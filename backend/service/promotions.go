@@ -0,0 +1,227 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+// Promotions are applied when an order is priced, before manual discounts and tax:
+//
+//   - buy-X-get-Y, bundle and sale promotions are item promotions. They are tried in
+//     priority order and each order line takes part in at most one of them;
+//   - of the spend thresholds the order reaches, only the one giving the most applies. It
+//     is worked out on what is left after item promotions and shared over the lines;
+//   - what a promotion takes off is recorded per line, so refunds give back the share of
+//     the discounted price and reports can total each promotion.
+
+// promotionActive reports whether p applies at the given time.
+func promotionActive(p model.Promotion, at time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && at.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return false
+	}
+	if p.DailyFrom == "" || p.DailyTo == "" {
+		return true
+	}
+	clock := at.Format("15:04")
+	if p.DailyFrom <= p.DailyTo {
+		return clock >= p.DailyFrom && clock < p.DailyTo
+	}
+	// the window runs past midnight, e.g. 22:00-02:00
+	return clock >= p.DailyFrom || clock < p.DailyTo
+}
+
+// promotionQualifies reports whether product is listed on p or in its category, including
+// the category's subcategories.
+func promotionQualifies(p model.Promotion, product *model.Product, categories categoryTree) bool {
+	if len(p.Products) == 0 && p.CategoryID == nil {
+		return true
+	}
+	for _, q := range p.Products {
+		if q.ID == product.ID {
+			return true
+		}
+	}
+	return p.CategoryID != nil && categories.within(product.CategoryID, *p.CategoryID)
+}
+
+// promoUnit is a single unit of an order line, so multi-buy offers can group units
+// across lines.
+type promoUnit struct {
+	line  int
+	price model.Money
+}
+
+// promoCart is the state of an order while promotions are applied to it.
+type promoCart struct {
+	lines      []model.OrderItem
+	products   []*model.Product
+	categories categoryTree
+	claimed    []bool
+}
+
+// units lists the units of unclaimed lines that qualify for p, most expensive first. Only
+// whole units count, so a weighed line of 0.375 kg takes no part in a multi-buy.
+func (c *promoCart) units(p model.Promotion) []promoUnit {
+	var units []promoUnit
+	for i, line := range c.lines {
+		if c.claimed[i] || !promotionQualifies(p, c.products[i], c.categories) {
+			continue
+		}
+		for n := 0; n < line.Quantity.Int(); n++ {
+			units = append(units, promoUnit{line: i, price: line.Price})
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].price > units[j].price })
+	return units
+}
+
+// record claims the lines that took part in p and adds what it took off each of them.
+func (c *promoCart) record(p model.Promotion, used []bool, off []model.Money) {
+	for i := range c.lines {
+		if used[i] {
+			c.claimed[i] = true
+		}
+		if off[i] <= 0 {
+			continue
+		}
+		line := &c.lines[i]
+		line.PromotionDiscount += off[i]
+		line.Promotions = append(line.Promotions, model.OrderItemPromotion{
+			PromotionID: p.ID,
+			Name:        p.Name,
+			Amount:      off[i],
+		})
+	}
+}
+
+// remaining is what is left of each line after promotions so far.
+func (c *promoCart) remaining() []model.Money {
+	left := make([]model.Money, len(c.lines))
+	for i, line := range c.lines {
+		left[i] = line.Price.Mul(line.Quantity) - line.PromotionDiscount
+	}
+	return left
+}
+
+// applyPromotions applies the promotions active at the given time to the priced lines.
+func applyPromotions(lines []model.OrderItem, products []*model.Product, categories categoryTree, promos []model.Promotion, at time.Time) {
+	cart := &promoCart{lines: lines, products: products, categories: categories, claimed: make([]bool, len(lines))}
+	sorted := append([]model.Promotion(nil), promos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var spend []model.Promotion
+	for _, p := range sorted {
+		if !promotionActive(p, at) {
+			continue
+		}
+		used := make([]bool, len(lines))
+		off := make([]model.Money, len(lines))
+		switch p.Type {
+		case model.PromotionBuyXGetY:
+			applyBuyXGetY(p, cart.units(p), used, off)
+		case model.PromotionBundle:
+			applyBundle(p, cart.units(p), used, off)
+		case model.PromotionSale:
+			for i, line := range cart.lines {
+				if !cart.claimed[i] && promotionQualifies(p, cart.products[i], cart.categories) {
+					used[i] = true
+					off[i] = p.Percent.Of(line.Price.Mul(line.Quantity))
+				}
+			}
+		case model.PromotionSpendThreshold:
+			spend = append(spend, p)
+			continue
+		}
+		cart.record(p, used, off)
+	}
+
+	applySpendThreshold(cart, spend)
+}
+
+// applyBuyXGetY makes the cheapest GetQuantity units of every full group of
+// BuyQuantity+GetQuantity units free.
+func applyBuyXGetY(p model.Promotion, units []promoUnit, used []bool, off []model.Money) {
+	group := p.BuyQuantity + p.GetQuantity
+	if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+		return
+	}
+	for start := 0; start+group <= len(units); start += group {
+		for k, u := range units[start : start+group] {
+			used[u.line] = true
+			if k >= p.BuyQuantity {
+				off[u.line] += u.price
+			}
+		}
+	}
+}
+
+// applyBundle prices every full group of BundleQuantity units at BundlePrice, sharing the
+// saving over the units of the group by price.
+func applyBundle(p model.Promotion, units []promoUnit, used []bool, off []model.Money) {
+	if p.BundleQuantity <= 0 {
+		return
+	}
+	for start := 0; start+p.BundleQuantity <= len(units); start += p.BundleQuantity {
+		group := units[start : start+p.BundleQuantity]
+		prices := make([]model.Money, len(group))
+		var sum model.Money
+		for k, u := range group {
+			prices[k] = u.price
+			sum += u.price
+		}
+		if sum <= p.BundlePrice {
+			continue
+		}
+		for k, share := range allocate(sum-p.BundlePrice, prices) {
+			used[group[k].line] = true
+			off[group[k].line] += share
+		}
+	}
+}
+
+// applySpendThreshold applies the spend threshold worth the most to the order, if any is
+// reached.
+func applySpendThreshold(cart *promoCart, spend []model.Promotion) {
+	left := cart.remaining()
+	var total model.Money
+	for _, m := range left {
+		total += m
+	}
+
+	var best *model.Promotion
+	var bestOff model.Money
+	for i, p := range spend {
+		if total <= 0 || total < p.MinSpend {
+			continue
+		}
+		amount := p.Amount
+		if p.Percent > 0 {
+			amount = p.Percent.Of(total)
+		}
+		if amount > total {
+			amount = total
+		}
+		if amount > bestOff {
+			best, bestOff = &spend[i], amount
+		}
+	}
+	if best == nil {
+		return
+	}
+
+	used := make([]bool, len(cart.lines))
+	cart.record(*best, used, allocate(bestOff, left))
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+func TestPromotionActive(t *testing.T) {
+	at := time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC)
+	before := at.Add(-time.Hour)
+	after := at.Add(time.Hour)
+	tests := []struct {
+		name string
+		p    model.Promotion
+		want bool
+	}{
+		{name: "inactive", p: model.Promotion{}, want: false},
+		{name: "always", p: model.Promotion{Active: true}, want: true},
+		{name: "not started", p: model.Promotion{Active: true, StartsAt: &after}, want: false},
+		{name: "started", p: model.Promotion{Active: true, StartsAt: &before, EndsAt: &after}, want: true},
+		{name: "ends now", p: model.Promotion{Active: true, EndsAt: &at}, want: false},
+		{name: "in daily window", p: model.Promotion{Active: true, DailyFrom: "23:00", DailyTo: "23:59"}, want: true},
+		{name: "outside daily window", p: model.Promotion{Active: true, DailyFrom: "17:00", DailyTo: "19:00"}, want: false},
+		{name: "window past midnight", p: model.Promotion{Active: true, DailyFrom: "22:00", DailyTo: "02:00"}, want: true},
+		{name: "window ends on the minute", p: model.Promotion{Active: true, DailyFrom: "20:00", DailyTo: "23:30"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := promotionActive(tt.p, at); got != tt.want {
+			t.Errorf("%s: promotionActive = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// promoLine is an order line for a product with its own id, in category when set.
+type promoLine struct {
+	price    model.Money
+	qty      model.Quantity
+	category uint
+}
+
+func TestApplyPromotions(t *testing.T) {
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	drinks, soda := uint(2), uint(3)
+	categories := newCategoryTree([]model.Category{
+		{ID: 1, Name: "Food"},
+		{ID: drinks, Name: "Drinks"},
+		{ID: soda, Name: "Soda", ParentID: &drinks},
+	})
+	buy2get1 := model.Promotion{ID: 1, Type: model.PromotionBuyXGetY, Active: true, BuyQuantity: 2, GetQuantity: 1}
+	bundle := model.Promotion{ID: 2, Type: model.PromotionBundle, Active: true, BundleQuantity: 3, BundlePrice: 1000}
+	drinksSale := model.Promotion{ID: 3, Type: model.PromotionSale, Active: true, Percent: 100000, CategoryID: &drinks}
+	tenPercentOver2000 := model.Promotion{ID: 4, Type: model.PromotionSpendThreshold, Active: true, MinSpend: 2000, Percent: 100000}
+	fiveHundredOver3000 := model.Promotion{ID: 5, Type: model.PromotionSpendThreshold, Active: true, MinSpend: 3000, Amount: 500}
+	first := func(p model.Promotion) model.Promotion { p.Priority = -1; return p }
+	inactive := func(p model.Promotion) model.Promotion { p.Active = false; return p }
+
+	tests := []struct {
+		name   string
+		lines  []promoLine
+		promos []model.Promotion
+		want   []model.Money
+	}{
+		{
+			name:   "buy two get one on one line",
+			lines:  []promoLine{{price: 300, qty: model.Units(3)}},
+			promos: []model.Promotion{buy2get1},
+			want:   []model.Money{300},
+		},
+		{
+			name:   "cheapest unit across lines is free",
+			lines:  []promoLine{{price: 500, qty: model.Units(2)}, {price: 200, qty: model.Units(1)}},
+			promos: []model.Promotion{buy2get1},
+			want:   []model.Money{0, 200},
+		},
+		{
+			name:   "only full groups count",
+			lines:  []promoLine{{price: 300, qty: model.Units(5)}},
+			promos: []model.Promotion{buy2get1},
+			want:   []model.Money{300},
+		},
+		{
+			name:   "weighed quantities take no part in a multi-buy",
+			lines:  []promoLine{{price: 300, qty: 2500}},
+			promos: []model.Promotion{buy2get1},
+			want:   []model.Money{0},
+		},
+		{
+			name:   "bundle saving shared by price",
+			lines:  []promoLine{{price: 500, qty: model.Units(1)}, {price: 400, qty: model.Units(1)}, {price: 300, qty: model.Units(1)}},
+			promos: []model.Promotion{bundle},
+			want:   []model.Money{83, 67, 50},
+		},
+		{
+			name:   "bundle dearer than the units is skipped",
+			lines:  []promoLine{{price: 300, qty: model.Units(3)}},
+			promos: []model.Promotion{bundle},
+			want:   []model.Money{0},
+		},
+		{
+			name:   "sale covers subcategories",
+			lines:  []promoLine{{price: 1000, qty: model.Units(2), category: soda}, {price: 1000, qty: model.Units(1), category: 1}},
+			promos: []model.Promotion{drinksSale},
+			want:   []model.Money{200, 0},
+		},
+		{
+			name:   "a line takes part in one item promotion, by priority",
+			lines:  []promoLine{{price: 300, qty: model.Units(3), category: soda}},
+			promos: []model.Promotion{buy2get1, first(drinksSale)},
+			want:   []model.Money{90},
+		},
+		{
+			name:   "inactive promotions are skipped",
+			lines:  []promoLine{{price: 300, qty: model.Units(3)}},
+			promos: []model.Promotion{inactive(buy2get1)},
+			want:   []model.Money{0},
+		},
+		{
+			name:   "spend threshold shared over the lines",
+			lines:  []promoLine{{price: 1000, qty: model.Units(1)}, {price: 3000, qty: model.Units(1)}},
+			promos: []model.Promotion{tenPercentOver2000},
+			want:   []model.Money{100, 300},
+		},
+		{
+			name:   "only the best spend threshold applies",
+			lines:  []promoLine{{price: 1000, qty: model.Units(1)}, {price: 3000, qty: model.Units(1)}},
+			promos: []model.Promotion{tenPercentOver2000, fiveHundredOver3000},
+			want:   []model.Money{125, 375},
+		},
+		{
+			name:   "spend threshold on what item promotions leave",
+			lines:  []promoLine{{price: 300, qty: model.Units(3)}, {price: 1000, qty: model.Units(1)}},
+			promos: []model.Promotion{buy2get1, {ID: 6, Type: model.PromotionSpendThreshold, Active: true, MinSpend: 1600, Amount: 160}},
+			want:   []model.Money{360, 100},
+		},
+		{
+			name:   "spend threshold not reached after item promotions",
+			lines:  []promoLine{{price: 300, qty: model.Units(3)}, {price: 1000, qty: model.Units(1)}},
+			promos: []model.Promotion{buy2get1, {ID: 6, Type: model.PromotionSpendThreshold, Active: true, MinSpend: 1700, Amount: 160}},
+			want:   []model.Money{300, 0},
+		},
+	}
+	for _, tt := range tests {
+		lines := make([]model.OrderItem, len(tt.lines))
+		products := make([]*model.Product, len(tt.lines))
+		for i, l := range tt.lines {
+			p := &model.Product{ID: uint(i + 1), Price: l.price}
+			if l.category != 0 {
+				category := l.category
+				p.CategoryID = &category
+			}
+			products[i] = p
+			lines[i] = model.OrderItem{ProductID: p.ID, Price: l.price, Quantity: l.qty}
+		}
+		applyPromotions(lines, products, categories, tt.promos, at)
+		for i, line := range lines {
+			if line.PromotionDiscount != tt.want[i] {
+				t.Errorf("%s: line %d discount = %d, want %d", tt.name, i, line.PromotionDiscount, tt.want[i])
+			}
+			var recorded model.Money
+			for _, p := range line.Promotions {
+				recorded += p.Amount
+			}
+			if recorded != line.PromotionDiscount {
+				t.Errorf("%s: line %d records %d of promotions, discount is %d", tt.name, i, recorded, line.PromotionDiscount)
+			}
+		}
+	}
+}
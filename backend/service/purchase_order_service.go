@@ -0,0 +1,304 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(input dto.CreatePurchaseOrderDTO, userID uint) (*model.PurchaseOrder, error)
+	UpdatePurchaseOrder(id uint, input dto.CreatePurchaseOrderDTO) (*model.PurchaseOrder, error)
+	GetPurchaseOrder(id uint) (*model.PurchaseOrder, error)
+	ListPurchaseOrders(status string) ([]model.PurchaseOrder, error)
+	SendPurchaseOrder(id uint) (*model.PurchaseOrder, error)
+	CancelPurchaseOrder(id uint) (*model.PurchaseOrder, error)
+	ReceivePurchaseOrder(id uint, input dto.ReceivePurchaseOrderDTO, userID uint) (*model.GoodsReceipt, error)
+}
+
+type purchaseOrderServiceImpl struct {
+	db           *gorm.DB
+	poRepo       repository.PurchaseOrderRepository
+	supplierRepo repository.SupplierRepository
+	prodRepo     repository.ProductRepository
+	locationRepo repository.LocationRepository
+}
+
+func NewPurchaseOrderService(db *gorm.DB, por repository.PurchaseOrderRepository, sr repository.SupplierRepository, pr repository.ProductRepository, lr repository.LocationRepository) PurchaseOrderService {
+	return &purchaseOrderServiceImpl{db: db, poRepo: por, supplierRepo: sr, prodRepo: pr, locationRepo: lr}
+}
+
+// purchaseOrderTransitions lists the statuses a purchase order in a given status may move
+// to. Cancelling a partially received order closes it; what was received stays in stock.
+var purchaseOrderTransitions = map[string][]string{
+	model.PurchaseOrderDraft:             {model.PurchaseOrderSent, model.PurchaseOrderCancelled},
+	model.PurchaseOrderSent:              {model.PurchaseOrderPartiallyReceived, model.PurchaseOrderReceived, model.PurchaseOrderCancelled},
+	model.PurchaseOrderPartiallyReceived: {model.PurchaseOrderPartiallyReceived, model.PurchaseOrderReceived, model.PurchaseOrderCancelled},
+}
+
+// transitionPurchaseOrder enforces purchaseOrderTransitions and moves the order to "to",
+// conditional on the status that was read.
+func transitionPurchaseOrder(poRepo repository.PurchaseOrderRepository, po *model.PurchaseOrder, to string) error {
+	allowed := false
+	for _, s := range purchaseOrderTransitions[po.Status] {
+		if s == to {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("purchase order %d is %s and cannot become %s", po.ID, po.Status, to)
+	}
+	if err := poRepo.UpdateStatus(po.ID, []string{po.Status}, to); err != nil {
+		return err
+	}
+	po.Status = to
+	return nil
+}
+
+// buildItems checks the supplier, location and products and returns the delivery location,
+// the lines and their total. Lines are in each product's purchase unit and keep its pack
+// size, so changing it later does not change what was ordered.
+func (s *purchaseOrderServiceImpl) buildItems(input dto.CreatePurchaseOrderDTO) (uint, []model.PurchaseOrderItem, model.Money, error) {
+	if _, err := s.supplierRepo.GetByID(input.SupplierID); err != nil {
+		return 0, nil, 0, fmt.Errorf("supplier %d: %w", input.SupplierID, err)
+	}
+	location, err := findLocation(s.locationRepo, input.LocationID)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	items := make([]model.PurchaseOrderItem, 0, len(input.Items))
+	var total model.Money
+	for _, it := range input.Items {
+		product, err := s.prodRepo.GetByID(it.ProductID)
+		if err != nil {
+			return 0, nil, 0, fmt.Errorf("product %d: %w", it.ProductID, err)
+		}
+		if product.HasVariants {
+			return 0, nil, 0, fmt.Errorf("product %d has variants: order one of them", it.ProductID)
+		}
+		if product.IsKit {
+			return 0, nil, 0, fmt.Errorf("product %d is a kit: order its components", it.ProductID)
+		}
+		if err := product.CheckQuantity(it.Quantity.Mul(product.UnitsPerPack())); err != nil {
+			return 0, nil, 0, err
+		}
+		items = append(items, model.PurchaseOrderItem{
+			ProductID: it.ProductID,
+			Quantity:  it.Quantity,
+			UnitCost:  it.UnitCost,
+			PackSize:  product.UnitsPerPack(),
+		})
+		total += it.UnitCost.Mul(it.Quantity)
+	}
+	return location.ID, items, total, nil
+}
+
+func (s *purchaseOrderServiceImpl) CreatePurchaseOrder(input dto.CreatePurchaseOrderDTO, userID uint) (*model.PurchaseOrder, error) {
+	locationID, items, total, err := s.buildItems(input)
+	if err != nil {
+		return nil, err
+	}
+	po := model.PurchaseOrder{
+		SupplierID: input.SupplierID,
+		LocationID: locationID,
+		Status:     model.PurchaseOrderDraft,
+		Reference:  input.Reference,
+		Notes:      input.Notes,
+		Total:      total,
+		CreatedBy:  &userID,
+		Items:      items,
+	}
+	if err := s.poRepo.Create(&po); err != nil {
+		return nil, err
+	}
+	return s.poRepo.GetByID(po.ID)
+}
+
+// UpdatePurchaseOrder replaces the supplier, details and lines of a draft.
+func (s *purchaseOrderServiceImpl) UpdatePurchaseOrder(id uint, input dto.CreatePurchaseOrderDTO) (*model.PurchaseOrder, error) {
+	locationID, items, total, err := s.buildItems(input)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		txPORepo := impl.NewPurchaseOrderRepoImpl(tx)
+		po, err := txPORepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		// lock the draft so it cannot be sent while it is being edited
+		if err := transitionPurchaseOrder(txPORepo, po, model.PurchaseOrderDraft); err != nil {
+			return err
+		}
+		po.SupplierID = input.SupplierID
+		po.LocationID = locationID
+		po.Reference = input.Reference
+		po.Notes = input.Notes
+		po.Total = total
+		if err := txPORepo.Update(po); err != nil {
+			return err
+		}
+		return txPORepo.ReplaceItems(po.ID, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.poRepo.GetByID(id)
+}
+
+func (s *purchaseOrderServiceImpl) GetPurchaseOrder(id uint) (*model.PurchaseOrder, error) {
+	return s.poRepo.GetByID(id)
+}
+
+func (s *purchaseOrderServiceImpl) ListPurchaseOrders(status string) ([]model.PurchaseOrder, error) {
+	return s.poRepo.List(status)
+}
+
+func (s *purchaseOrderServiceImpl) SendPurchaseOrder(id uint) (*model.PurchaseOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txPORepo := impl.NewPurchaseOrderRepoImpl(tx)
+		po, err := txPORepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := transitionPurchaseOrder(txPORepo, po, model.PurchaseOrderSent); err != nil {
+			return err
+		}
+		now := time.Now()
+		po.SentAt = &now
+		return txPORepo.Update(po)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.poRepo.GetByID(id)
+}
+
+func (s *purchaseOrderServiceImpl) CancelPurchaseOrder(id uint) (*model.PurchaseOrder, error) {
+	po, err := s.poRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := transitionPurchaseOrder(s.poRepo, po, model.PurchaseOrderCancelled); err != nil {
+		return nil, err
+	}
+	return s.poRepo.GetByID(id)
+}
+
+// ReceivePurchaseOrder books a delivery against a sent order. Each line may be received
+// in several deliveries but never beyond what was ordered; deliveries of one order are
+// booked one at a time. The stock comes in through the ledger as receipt movements
+// referencing the goods receipt, in the product's own unit: a line ordered by the case
+// brings in the units of each case.
+func (s *purchaseOrderServiceImpl) ReceivePurchaseOrder(id uint, input dto.ReceivePurchaseOrderDTO, userID uint) (*model.GoodsReceipt, error) {
+	var receipt model.GoodsReceipt
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txPORepo := impl.NewPurchaseOrderRepoImpl(tx)
+		po, err := txPORepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if po.Status != model.PurchaseOrderSent && po.Status != model.PurchaseOrderPartiallyReceived {
+			return fmt.Errorf("purchase order %d is %s and cannot be received", po.ID, po.Status)
+		}
+
+		lines := make(map[uint]*model.PurchaseOrderItem, len(po.Items))
+		for i := range po.Items {
+			lines[po.Items[i].ID] = &po.Items[i]
+		}
+		ledger := newStockLedger(tx, userID)
+		items := make([]model.GoodsReceiptItem, 0, len(input.Items))
+		serials := make([][]string, 0, len(input.Items))
+		for _, it := range input.Items {
+			line, ok := lines[it.PurchaseOrderItemID]
+			if !ok {
+				return fmt.Errorf("item %d is not on purchase order %d", it.PurchaseOrderItemID, po.ID)
+			}
+			if err := txPORepo.AddReceivedQuantity(line.ID, it.Quantity); err != nil {
+				return fmt.Errorf("item %d: %w", line.ID, err)
+			}
+			line.ReceivedQuantity += it.Quantity
+			lotID, err := ledger.lot(line.ProductID, po.LocationID, it.Lot)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", line.ID, err)
+			}
+			items = append(items, model.GoodsReceiptItem{
+				PurchaseOrderItemID: line.ID,
+				ProductID:           line.ProductID,
+				Quantity:            it.Quantity,
+				StockQuantity:       it.Quantity.Mul(line.PackSize),
+				UnitCost:            line.UnitCost,
+				LotID:               lotID,
+			})
+			serials = append(serials, it.Serials)
+		}
+		landItems(items, input.AdditionalCosts)
+
+		receipt = model.GoodsReceipt{
+			PurchaseOrderID: po.ID,
+			UserID:          userID,
+			AdditionalCosts: input.AdditionalCosts,
+			Note:            input.Note,
+			Items:           items,
+		}
+		if err := txPORepo.CreateReceipt(&receipt); err != nil {
+			return err
+		}
+
+		// received stock is costed at its landed cost
+		for i, it := range receipt.Items {
+			if err := ledger.recordAt(&model.StockMovement{
+				ProductID:  it.ProductID,
+				LocationID: po.LocationID,
+				Type:       model.StockReceipt,
+				Quantity:   it.StockQuantity,
+				LotID:      it.LotID,
+				Serials:    serials[i],
+				RefType:    "goods_receipt",
+				RefID:      &receipt.ID,
+			}, it.LandedCost); err != nil {
+				return err
+			}
+		}
+
+		status := model.PurchaseOrderReceived
+		for _, line := range po.Items {
+			if line.ReceivedQuantity < line.Quantity {
+				status = model.PurchaseOrderPartiallyReceived
+			}
+		}
+		return transitionPurchaseOrder(txPORepo, po, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// landItems shares the additional costs over the received lines by value, or by quantity
+// when every line is free, and sets each line's landed cost and its cost per unit of stock.
+func landItems(items []model.GoodsReceiptItem, additional model.Money) {
+	weights := make([]model.Money, len(items))
+	var value model.Money
+	for i, it := range items {
+		weights[i] = it.UnitCost.Mul(it.Quantity)
+		value += weights[i]
+	}
+	if value == 0 {
+		for i, it := range items {
+			weights[i] = model.Money(it.StockQuantity)
+		}
+	}
+	for i, share := range allocate(additional, weights) {
+		it := &items[i]
+		it.LandedCost = it.UnitCost.Mul(it.Quantity) + share
+		if it.StockQuantity != 0 {
+			it.LandedUnitCost = it.LandedCost.MulRate(int64(model.OneUnit), int64(it.StockQuantity))
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type ReorderService interface {
+	LowStock() ([]model.Product, error)
+	GenerateSuggestions(userID uint) (*model.ReorderSuggestions, error)
+}
+
+type reorderServiceImpl struct {
+	db           *gorm.DB
+	prodRepo     repository.ProductRepository
+	orderRepo    repository.OrderRepository
+	poRepo       repository.PurchaseOrderRepository
+	locationRepo repository.LocationRepository
+}
+
+func NewReorderService(db *gorm.DB, pr repository.ProductRepository, or repository.OrderRepository, por repository.PurchaseOrderRepository, lr repository.LocationRepository) ReorderService {
+	return &reorderServiceImpl{db: db, prodRepo: pr, orderRepo: or, poRepo: por, locationRepo: lr}
+}
+
+// envDays reads a positive number of days from the environment.
+func envDays(key string, def int) int {
+	if days, err := strconv.Atoi(os.Getenv(key)); err == nil && days > 0 {
+		return days
+	}
+	return def
+}
+
+func (s *reorderServiceImpl) LowStock() ([]model.Product, error) {
+	return s.prodRepo.ListLowStock()
+}
+
+// reorderQuantity is how much to order of a product that is low on stock: enough to
+// cover coverDays at the rate it sold over salesDays, at least its reorder quantity, and
+// never less than what takes it back above its reorder point. It is in the product's
+// purchase unit, rounded up to whole packs.
+func reorderQuantity(p model.Product, sold, onOrder model.Quantity, salesDays, coverDays int) model.Quantity {
+	qty := p.ReorderQuantity
+	if sold > 0 {
+		// round up: a partly covered day still needs stock
+		days := model.Quantity(salesDays)
+		if byVelocity := (sold*model.Quantity(coverDays) + days - 1) / days; byVelocity > qty {
+			qty = byVelocity
+		}
+	}
+	if short := p.ReorderPoint - p.Stock - onOrder + p.Step(); short > qty {
+		qty = short
+	}
+	if p.PackSize == 0 {
+		return qty.RoundUp(p.Step())
+	}
+	return model.Units(int((qty + p.PackSize - 1) / p.PackSize))
+}
+
+// GenerateSuggestions raises a draft purchase order per supplier for the products at or
+// below their reorder point. Stock already on order counts, so running it again does
+// not order the same shortfall twice. Drafts go to the default location; userID is 0 when
+// run by the job.
+func (s *reorderServiceImpl) GenerateSuggestions(userID uint) (*model.ReorderSuggestions, error) {
+	salesDays := envDays("REORDER_SALES_DAYS", 28)
+	coverDays := envDays("REORDER_COVER_DAYS", 14)
+	result := &model.ReorderSuggestions{PurchaseOrders: []model.PurchaseOrder{}, NoSupplier: []uint{}}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txPORepo := impl.NewPurchaseOrderRepoImpl(tx)
+		products, err := impl.NewProductRepoImpl(tx).ListLowStock()
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			return nil
+		}
+		sold, err := impl.NewOrderRepoImpl(tx).UnitsSold(time.Now().AddDate(0, 0, -salesDays))
+		if err != nil {
+			return err
+		}
+		onOrder, err := txPORepo.OnOrder()
+		if err != nil {
+			return err
+		}
+		location, err := findLocation(impl.NewLocationRepoImpl(tx), 0)
+		if err != nil {
+			return err
+		}
+
+		bySupplier := map[uint][]model.PurchaseOrderItem{}
+		var ids []uint
+		for _, p := range products {
+			if p.Stock+onOrder[p.ID] > p.ReorderPoint {
+				continue
+			}
+			if p.SupplierID == nil {
+				result.NoSupplier = append(result.NoSupplier, p.ID)
+				continue
+			}
+			bySupplier[*p.SupplierID] = append(bySupplier[*p.SupplierID], model.PurchaseOrderItem{
+				ProductID: p.ID,
+				Quantity:  reorderQuantity(p, sold[p.ID], onOrder[p.ID], salesDays, coverDays),
+				PackSize:  p.UnitsPerPack(),
+			})
+			ids = append(ids, p.ID)
+		}
+		costs, err := txPORepo.LastUnitCosts(ids)
+		if err != nil {
+			return err
+		}
+
+		suppliers := make([]uint, 0, len(bySupplier))
+		for id := range bySupplier {
+			suppliers = append(suppliers, id)
+		}
+		sort.Slice(suppliers, func(i, j int) bool { return suppliers[i] < suppliers[j] })
+		for _, supplierID := range suppliers {
+			po := model.PurchaseOrder{
+				SupplierID: supplierID,
+				LocationID: location.ID,
+				Status:     model.PurchaseOrderDraft,
+				Notes:      fmt.Sprintf("Suggested from sales over the last %d days", salesDays),
+				Suggested:  true,
+				Items:      bySupplier[supplierID],
+			}
+			if userID != 0 {
+				po.CreatedBy = &userID
+			}
+			for i := range po.Items {
+				po.Items[i].UnitCost = costs[po.Items[i].ProductID].Mul(po.Items[i].PackSize)
+				po.Total += po.Items[i].UnitCost.Mul(po.Items[i].Quantity)
+			}
+			if err := txPORepo.Create(&po); err != nil {
+				return err
+			}
+			result.PurchaseOrders = append(result.PurchaseOrders, po)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RunReorderJob raises reorder suggestions every REORDER_JOB_INTERVAL_HOURS (default 24;
+// 0 turns the job off). It blocks, so start it in its own goroutine.
+func RunReorderJob(svc ReorderService) {
+	hours := 24
+	if h, err := strconv.Atoi(os.Getenv("REORDER_JOB_INTERVAL_HOURS")); err == nil && h >= 0 {
+		hours = h
+	}
+	if hours == 0 {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(hours) * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		result, err := svc.GenerateSuggestions(0)
+		if err != nil {
+			log.Printf("reorder job: %v", err)
+			continue
+		}
+		log.Printf("reorder job: %d purchase orders suggested, %d products without a supplier",
+			len(result.PurchaseOrders), len(result.NoSupplier))
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+type ReportService interface {
+	SalesSummary(from, to *time.Time) (*model.SalesSummary, error)
+	PaymentsByTender(from, to *time.Time) ([]model.TenderTotal, error)
+	TaxByRate(from, to *time.Time) ([]model.TaxTotal, error)
+	Promotions(from, to *time.Time) ([]model.PromotionTotal, error)
+	GrossProfit(from, to *time.Time) ([]model.ProductProfit, error)
+	SalesByCategory(from, to *time.Time) ([]model.CategorySales, error)
+}
+
+type reportServiceImpl struct {
+	reportRepo   repository.ReportRepository
+	prodRepo     repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewReportService(rr repository.ReportRepository, pr repository.ProductRepository, cr repository.CategoryRepository) ReportService {
+	return &reportServiceImpl{reportRepo: rr, prodRepo: pr, categoryRepo: cr}
+}
+
+func (s *reportServiceImpl) SalesSummary(from, to *time.Time) (*model.SalesSummary, error) {
+	return s.reportRepo.SalesSummary(from, to)
+}
+
+func (s *reportServiceImpl) PaymentsByTender(from, to *time.Time) ([]model.TenderTotal, error) {
+	return s.reportRepo.PaymentsByTender(from, to)
+}
+
+func (s *reportServiceImpl) TaxByRate(from, to *time.Time) ([]model.TaxTotal, error) {
+	return s.reportRepo.TaxByRate(from, to)
+}
+
+func (s *reportServiceImpl) Promotions(from, to *time.Time) ([]model.PromotionTotal, error) {
+	return s.reportRepo.Promotions(from, to)
+}
+
+func (s *reportServiceImpl) GrossProfit(from, to *time.Time) ([]model.ProductProfit, error) {
+	return s.reportRepo.GrossProfit(from, to)
+}
+
+// SalesByCategory rolls the per-product figures up the category tree, so a category's
+// totals include its subcategories. Products count under the category they are in now.
+func (s *reportServiceImpl) SalesByCategory(from, to *time.Time) ([]model.CategorySales, error) {
+	profits, err := s.reportRepo.GrossProfit(from, to)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(profits))
+	for _, p := range profits {
+		ids = append(ids, p.ProductID)
+	}
+	products, err := s.prodRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	categoryOf := make(map[uint]*uint, len(products))
+	for _, p := range products {
+		categoryOf[p.ID] = p.CategoryID
+	}
+	categories, err := s.categoryRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	tree := newCategoryTree(categories)
+
+	byCategory := map[uint]*model.CategorySales{}
+	for _, c := range categories {
+		byCategory[c.ID] = &model.CategorySales{CategoryID: &c.ID, Name: c.Name, ParentID: c.ParentID}
+	}
+	none := &model.CategorySales{Name: "Uncategorised"}
+	add := func(row *model.CategorySales, p model.ProductProfit) {
+		row.Quantity += p.Quantity
+		row.NetSales += p.NetSales
+		row.CostOfGoods += p.CostOfGoods
+		row.GrossProfit += p.GrossProfit
+	}
+	for _, p := range profits {
+		path := tree.path(categoryOf[p.ProductID])
+		if len(path) == 0 {
+			add(none, p)
+		}
+		for _, c := range path {
+			add(byCategory[c.ID], p)
+		}
+	}
+
+	list := make([]model.CategorySales, 0, len(categories)+1)
+	for _, c := range categories {
+		list = append(list, *byCategory[c.ID])
+	}
+	if none.Quantity != 0 || none.NetSales != 0 {
+		list = append(list, *none)
+	}
+	return list, nil
+}
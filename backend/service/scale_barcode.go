@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+// scaleLabel is what a scale barcode reads: the product's seven-digit scale code and the
+// weight or price printed into the next five digits.
+type scaleLabel struct {
+	code  string
+	value int64
+	price bool
+}
+
+// scalePrefixes reads a comma-separated list of two-digit barcode prefixes from the
+// environment.
+func scalePrefixes(key, def string) []string {
+	list := os.Getenv(key)
+	if list == "" {
+		list = def
+	}
+	var prefixes []string
+	for _, p := range strings.Split(list, ",") {
+		prefixes = append(prefixes, strings.TrimSpace(p))
+	}
+	return prefixes
+}
+
+// parseScaleBarcode reads an EAN-13 code printed by a scale: a two-digit prefix, a five-digit
+// item code, a five-digit value and the check digit. The prefixes in SCALE_WEIGHT_PREFIXES
+// (default 21) carry a weight and those in SCALE_PRICE_PREFIXES (default 22) a price in
+// cents. Any other code is not a scale barcode.
+func parseScaleBarcode(code string) (scaleLabel, bool) {
+	if len(code) != 13 || !allDigits(code) || !gtinCheckDigitValid(code) {
+		return scaleLabel{}, false
+	}
+	value, _ := strconv.ParseInt(code[7:12], 10, 64)
+	label := scaleLabel{code: code[:7], value: value}
+	for _, p := range scalePrefixes("SCALE_WEIGHT_PREFIXES", "21") {
+		if code[:2] == p {
+			return label, true
+		}
+	}
+	for _, p := range scalePrefixes("SCALE_PRICE_PREFIXES", "22") {
+		if code[:2] == p {
+			label.price = true
+			return label, true
+		}
+	}
+	return scaleLabel{}, false
+}
+
+// quantity is how much of product p the label is for. A weight is in grams for a product
+// sold by the kilogram or gram, and likewise in millilitres or millimetres for one sold by
+// the litre or metre. A price is what the quantity cost at the product's price; a label
+// whose price no quantity of the product comes to exactly is refused rather than charged
+// a different amount.
+func (l scaleLabel) quantity(p *model.Product) (model.Quantity, error) {
+	if l.price {
+		if p.Price <= 0 {
+			return 0, fmt.Errorf("product %d has no price to weigh a priced label by", p.ID)
+		}
+		amount := model.Money(l.value)
+		qty := p.QuantityFor(amount)
+		if qty <= 0 || p.Price.Mul(qty) != amount {
+			return 0, fmt.Errorf("label price %s is not what any quantity of product %d costs at %s", amount, p.ID, p.Price)
+		}
+		return qty, nil
+	}
+	switch p.Unit {
+	case model.UnitKilogram, model.UnitLitre, model.UnitMetre:
+		return model.Quantity(l.value), nil
+	case model.UnitGram:
+		return model.Units(int(l.value)), nil
+	}
+	return 0, fmt.Errorf("product %d is sold %s and cannot be weighed", p.ID, p.Unit)
+}
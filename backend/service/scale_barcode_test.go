@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+func TestParseScaleBarcode(t *testing.T) {
+	tests := []struct {
+		code      string
+		wantOK    bool
+		wantCode  string
+		wantValue int64
+		wantPrice bool
+	}{
+		{code: "2112345012506", wantOK: true, wantCode: "2112345", wantValue: 1250},
+		{code: "2212345005994", wantOK: true, wantCode: "2212345", wantValue: 599, wantPrice: true},
+		{code: "2312345012500"},
+		{code: "2112345012507"},
+		{code: "211234501250"},
+		{code: "21123450125a6"},
+		{code: "4006381333931"},
+	}
+	for _, tt := range tests {
+		label, ok := parseScaleBarcode(tt.code)
+		if ok != tt.wantOK {
+			t.Errorf("parseScaleBarcode(%q) ok = %v, want %v", tt.code, ok, tt.wantOK)
+			continue
+		}
+		if ok && (label.code != tt.wantCode || label.value != tt.wantValue || label.price != tt.wantPrice) {
+			t.Errorf("parseScaleBarcode(%q) = %+v", tt.code, label)
+		}
+	}
+}
+
+func TestParseScaleBarcodePrefixes(t *testing.T) {
+	t.Setenv("SCALE_WEIGHT_PREFIXES", "23, 24")
+	t.Setenv("SCALE_PRICE_PREFIXES", "20")
+	tests := []struct {
+		code      string
+		wantOK    bool
+		wantPrice bool
+	}{
+		{code: "2312345012500", wantOK: true},
+		{code: "2012345012509", wantOK: true, wantPrice: true},
+		{code: "2112345012506"},
+		{code: "2212345005994"},
+	}
+	for _, tt := range tests {
+		label, ok := parseScaleBarcode(tt.code)
+		if ok != tt.wantOK || label.price != tt.wantPrice {
+			t.Errorf("parseScaleBarcode(%q) = %+v, %v, want ok %v, price %v", tt.code, label, ok, tt.wantOK, tt.wantPrice)
+		}
+	}
+}
+
+func TestScaleLabelQuantity(t *testing.T) {
+	tests := []struct {
+		name    string
+		label   scaleLabel
+		product model.Product
+		want    model.Quantity
+		wantErr bool
+	}{
+		{name: "grams of a kilogram product", label: scaleLabel{value: 1250}, product: model.Product{Unit: model.UnitKilogram, Precision: 3}, want: 1250},
+		{name: "millilitres of a litre product", label: scaleLabel{value: 330}, product: model.Product{Unit: model.UnitLitre, Precision: 3}, want: 330},
+		{name: "millimetres of a metre product", label: scaleLabel{value: 2500}, product: model.Product{Unit: model.UnitMetre, Precision: 3}, want: 2500},
+		{name: "grams of a gram product", label: scaleLabel{value: 250}, product: model.Product{Unit: model.UnitGram}, want: model.Units(250)},
+		{name: "weight of a product sold each", label: scaleLabel{value: 250}, product: model.Product{Unit: model.UnitEach}, wantErr: true},
+		{name: "price by the kilogram", label: scaleLabel{value: 599, price: true}, product: model.Product{Unit: model.UnitKilogram, Precision: 3, Price: 1299}, want: 461},
+		{name: "price of whole units", label: scaleLabel{value: 900, price: true}, product: model.Product{Unit: model.UnitEach, Price: 300}, want: model.Units(3)},
+		{name: "price no quantity comes to", label: scaleLabel{value: 1000, price: true}, product: model.Product{Unit: model.UnitEach, Price: 300}, wantErr: true},
+		{name: "price below the smallest step", label: scaleLabel{value: 10, price: true}, product: model.Product{Unit: model.UnitKilogram, Precision: 1, Price: 250}, wantErr: true},
+		{name: "product without a price", label: scaleLabel{value: 599, price: true}, product: model.Product{Unit: model.UnitKilogram, Precision: 3}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.label.quantity(&tt.product)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: quantity = %s, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: quantity = %d, %v, want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+type SerialService interface {
+	// History looks a serial number up across products, for warranty claims.
+	History(number string) ([]model.SerialHistory, error)
+	ListByProduct(productID uint, status string) ([]model.Serial, error)
+}
+
+type serialServiceImpl struct {
+	serialRepo repository.SerialRepository
+	prodRepo   repository.ProductRepository
+}
+
+func NewSerialService(sr repository.SerialRepository, pr repository.ProductRepository) SerialService {
+	return &serialServiceImpl{serialRepo: sr, prodRepo: pr}
+}
+
+func (s *serialServiceImpl) History(number string) ([]model.SerialHistory, error) {
+	serials, err := s.serialRepo.ListByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	if len(serials) == 0 {
+		return nil, fmt.Errorf("serial %s not found", number)
+	}
+	list := make([]model.SerialHistory, 0, len(serials))
+	for _, serial := range serials {
+		events, err := s.serialRepo.Events(serial.ID)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, model.SerialHistory{Serial: serial, Events: events})
+	}
+	return list, nil
+}
+
+func (s *serialServiceImpl) ListByProduct(productID uint, status string) ([]model.Serial, error) {
+	if _, err := s.prodRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.serialRepo.ListByProduct(productID, status)
+}
@@ -0,0 +1,554 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+// stockLedger is the only way stock changes: it updates the stock level at the movement's
+// location and Product.Stock, values the movement against the product's cost layers, keeps
+// the lots of batch-tracked products and the serials of serial-tracked ones, and appends
+// it. It works on the transaction it is built on, so all of that commits together.
+type stockLedger struct {
+	prodRepo   repository.ProductRepository
+	levelRepo  repository.StockLevelRepository
+	moveRepo   repository.StockMovementRepository
+	costRepo   repository.CostLayerRepository
+	lotRepo    repository.LotRepository
+	serialRepo repository.SerialRepository
+	method     string
+	userID     uint
+}
+
+func newStockLedger(tx *gorm.DB, userID uint) *stockLedger {
+	return &stockLedger{
+		prodRepo:   impl.NewProductRepoImpl(tx),
+		levelRepo:  impl.NewStockLevelRepoImpl(tx),
+		moveRepo:   impl.NewStockMovementRepoImpl(tx),
+		costRepo:   impl.NewCostLayerRepoImpl(tx),
+		lotRepo:    impl.NewLotRepoImpl(tx),
+		serialRepo: impl.NewSerialRepoImpl(tx),
+		method:     costingMethod(),
+		userID:     userID,
+	}
+}
+
+// costingMethod reads COSTING_METHOD; FIFO unless it is set to average.
+func costingMethod() string {
+	if os.Getenv("COSTING_METHOD") == model.CostingAverage {
+		return model.CostingAverage
+	}
+	return model.CostingFIFO
+}
+
+// record applies m.Quantity to the product's stock at m.LocationID and writes m. Stock
+// coming in is valued at the product's average cost; use recordAt when its cost is known.
+// Stock may not go below zero at any location; a zero quantity records nothing.
+func (l *stockLedger) record(m *model.StockMovement) error {
+	return l.apply(m, nil)
+}
+
+// recordAt records stock coming in that cost cost in total, e.g. a goods receipt.
+func (l *stockLedger) recordAt(m *model.StockMovement, cost model.Money) error {
+	return l.apply(m, &cost)
+}
+
+func (l *stockLedger) apply(m *model.StockMovement, cost *model.Money) error {
+	if m.LocationID == 0 {
+		return fmt.Errorf("product %d: stock movement has no location", m.ProductID)
+	}
+	// the product row is always locked before the level, so a sale and a receipt of the
+	// same product cannot deadlock
+	switch {
+	case m.Quantity < 0:
+		if err := l.prodRepo.ReduceStock(m.ProductID, -m.Quantity); err != nil {
+			return fmt.Errorf("product %d: %w", m.ProductID, err)
+		}
+		if err := l.levelRepo.Reduce(m.ProductID, m.LocationID, -m.Quantity); err != nil {
+			return fmt.Errorf("product %d: %w", m.ProductID, err)
+		}
+	case m.Quantity > 0:
+		if err := l.prodRepo.IncreaseStock(m.ProductID, m.Quantity); err != nil {
+			return fmt.Errorf("product %d: %w", m.ProductID, err)
+		}
+		if err := l.levelRepo.Increase(m.ProductID, m.LocationID, m.Quantity); err != nil {
+			return fmt.Errorf("product %d: %w", m.ProductID, err)
+		}
+	default:
+		return nil
+	}
+	// read after the stock update, which locks the product row, so Stock already includes m
+	p, err := l.prodRepo.GetByID(m.ProductID)
+	if err != nil {
+		return err
+	}
+	if p.HasVariants {
+		return fmt.Errorf("product %d has variants, which hold its stock", p.ID)
+	}
+	if p.IsKit {
+		return fmt.Errorf("product %d is a kit, whose components hold its stock", p.ID)
+	}
+	if err := p.CheckQuantity(m.Quantity); err != nil {
+		return err
+	}
+	// a transfer moves stock between locations; what it cost does not change
+	if m.Type != model.StockTransferOut && m.Type != model.StockTransferIn {
+		if err := l.value(p, m, cost); err != nil {
+			return fmt.Errorf("product %d: %w", m.ProductID, err)
+		}
+	}
+	if l.userID != 0 {
+		m.UserID = &l.userID
+	}
+	switch {
+	case p.BatchTracked:
+		err = l.applyLots(m)
+	case p.SerialTracked:
+		err = l.applySerials(m)
+	default:
+		return l.moveRepo.Create(m)
+	}
+	if err != nil {
+		return fmt.Errorf("product %d: %w", m.ProductID, err)
+	}
+	return nil
+}
+
+// value sets m.Cost and keeps the cost layers and average cost in step.
+func (l *stockLedger) value(p *model.Product, m *model.StockMovement, cost *model.Money) error {
+	if m.Quantity > 0 {
+		m.Cost = p.AverageCost.Mul(m.Quantity)
+		if cost != nil {
+			m.Cost = *cost
+		}
+		err := l.costRepo.Create(&model.CostLayer{
+			ProductID: m.ProductID,
+			Quantity:  m.Quantity,
+			Remaining: m.Quantity,
+			Cost:      m.Cost,
+			RefType:   m.RefType,
+			RefID:     m.RefID,
+		})
+		if err != nil {
+			return err
+		}
+		before := p.Stock - m.Quantity
+		if before < 0 {
+			before = 0
+		}
+		average := (p.AverageCost.Mul(before) + m.Cost).MulRate(int64(model.OneUnit), int64(before+m.Quantity))
+		return l.prodRepo.SetAverageCost(m.ProductID, average)
+	}
+
+	fifo, err := l.consume(p, -m.Quantity)
+	if err != nil {
+		return err
+	}
+	m.Cost = -fifo
+	if l.method == model.CostingAverage {
+		m.Cost = -p.AverageCost.Mul(-m.Quantity)
+	}
+	return nil
+}
+
+// applyLots moves m's stock in or out of lots and writes it. Stock coming in goes into
+// m.LotID; a stocktake surplus, refund or void without one goes into the lot at the location
+// expiring last. Stock going out comes from m.LotID or, without one, from the lots expiring
+// first, written as one movement per lot. Sales and transfers skip expired lots, so expired
+// stock can only be adjusted or counted out.
+func (l *stockLedger) applyLots(m *model.StockMovement) error {
+	if m.Quantity > 0 {
+		if m.LotID == nil && (m.Type == model.StockStocktake || m.Type == model.StockRefund || m.Type == model.StockVoid) {
+			lot, err := l.lastLotAt(m.ProductID, m.LocationID)
+			if err != nil {
+				return err
+			}
+			m.LotID = lot
+		}
+		if m.LotID == nil {
+			return fmt.Errorf("batch-tracked stock needs a lot")
+		}
+		if err := l.checkLot(*m.LotID, m); err != nil {
+			return err
+		}
+		if err := l.lotRepo.Increase(*m.LotID, m.Quantity); err != nil {
+			return err
+		}
+		return l.moveRepo.Create(m)
+	}
+
+	if m.LotID != nil {
+		if err := l.checkLot(*m.LotID, m); err != nil {
+			return err
+		}
+		if err := l.lotRepo.Reduce(*m.LotID, -m.Quantity); err != nil {
+			return err
+		}
+		return l.moveRepo.Create(m)
+	}
+
+	sellable := m.Type == model.StockSale || m.Type == model.StockTransferOut
+	lots, err := l.lotRepo.ListOpen(m.ProductID, m.LocationID, sellable)
+	if err != nil {
+		return err
+	}
+	qty, taken := -m.Quantity, model.Quantity(0)
+	for _, lot := range lots {
+		if taken == qty {
+			break
+		}
+		take := min(lot.Quantity, qty-taken)
+		if err := l.lotRepo.Reduce(lot.ID, take); err != nil {
+			return err
+		}
+		part := *m
+		part.LotID = &lot.ID
+		part.Quantity = -take
+		part.Cost = prorate(m.Cost, taken, taken+take, qty)
+		if err := l.moveRepo.Create(&part); err != nil {
+			return err
+		}
+		taken += take
+	}
+	if taken < qty {
+		if sellable {
+			return fmt.Errorf("only %s in unexpired lots at location %d", taken, m.LocationID)
+		}
+		return fmt.Errorf("only %s in lots at location %d", taken, m.LocationID)
+	}
+	return nil
+}
+
+func (l *stockLedger) checkLot(id uint, m *model.StockMovement) error {
+	lot, err := l.lotRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("lot %d: %w", id, err)
+	}
+	if lot.ProductID != m.ProductID || lot.LocationID != m.LocationID {
+		return fmt.Errorf("lot %d is not of this product at location %d", id, m.LocationID)
+	}
+	return nil
+}
+
+// lastLotAt is the product's lot at the location expiring last, nil when it has none.
+func (l *stockLedger) lastLotAt(productID, locationID uint) (*uint, error) {
+	lots, err := l.lotRepo.ListByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, lot := range lots {
+		if lot.LocationID == locationID {
+			ids = append(ids, lot.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	lot, err := l.lotRepo.LastExpiring(ids)
+	if err != nil {
+		return nil, err
+	}
+	return &lot.ID, nil
+}
+
+// lot finds or creates the lot stock of a product comes in as at a location; nil when in
+// is nil or the product is not batch-tracked. The expiry date may be left out for a lot
+// already recorded there.
+func (l *stockLedger) lot(productID, locationID uint, in *dto.LotDTO) (*uint, error) {
+	if in == nil {
+		return nil, nil
+	}
+	p, err := l.prodRepo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.BatchTracked {
+		return nil, nil
+	}
+	var expiresOn *time.Time
+	if in.ExpiresOn != "" {
+		t, err := time.Parse("2006-01-02", in.ExpiresOn)
+		if err != nil {
+			return nil, err
+		}
+		expiresOn = &t
+	}
+	return l.lotAt(productID, locationID, in.Number, expiresOn)
+}
+
+func (l *stockLedger) lotAt(productID, locationID uint, number string, expiresOn *time.Time) (*uint, error) {
+	lot := model.Lot{ProductID: productID, LocationID: locationID, Number: number, ExpiresOn: expiresOn}
+	if err := l.lotRepo.FindOrCreate(&lot); err != nil {
+		return nil, err
+	}
+	if expiresOn != nil && (lot.ExpiresOn == nil || !lot.ExpiresOn.Equal(*expiresOn)) {
+		return nil, fmt.Errorf("lot %s of product %d is already recorded with another expiry date", number, productID)
+	}
+	return &lot.ID, nil
+}
+
+// soldLot is the lot stock sold on an order goes back into: of the lots the sale took the
+// product from, the one expiring last. It is nil when the sale took it from no lot.
+func (l *stockLedger) soldLot(orderID, productID uint) (*uint, error) {
+	moves, err := l.moveRepo.ListByRef("order", orderID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, mv := range moves {
+		if mv.Type == model.StockSale && mv.ProductID == productID && mv.LotID != nil {
+			ids = append(ids, *mv.LotID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	lot, err := l.lotRepo.LastExpiring(ids)
+	if err != nil {
+		return nil, err
+	}
+	return &lot.ID, nil
+}
+
+// serialMoves is the status each movement type moves a serial to. Stock coming in is
+// always in stock at the movement's location afterwards.
+var serialMoves = map[string]string{
+	model.StockSale:        model.SerialSold,
+	model.StockTransferOut: model.SerialInTransit,
+	model.StockAdjustment:  model.SerialRemoved,
+	model.StockStocktake:   model.SerialRemoved,
+}
+
+// serialSources lists the statuses a serial may come in from for each movement type; a
+// receipt, opening or found adjustment may also bring in a serial never seen before.
+var serialSources = map[string][]string{
+	model.StockOpening:    {model.SerialRemoved},
+	model.StockReceipt:    {model.SerialSold, model.SerialRemoved},
+	model.StockAdjustment: {model.SerialRemoved},
+	model.StockStocktake:  {model.SerialRemoved},
+	model.StockRefund:     {model.SerialSold},
+	model.StockVoid:       {model.SerialSold},
+	model.StockTransferIn: {model.SerialInTransit},
+}
+
+// applySerials writes m and moves each of m.Serials with it: one serial per unit, each in
+// stock at m's location before it goes out, and not in stock anywhere before it comes in.
+func (l *stockLedger) applySerials(m *model.StockMovement) error {
+	qty := m.Quantity
+	if qty < 0 {
+		qty = -qty
+	}
+	if model.Units(len(m.Serials)) != qty {
+		return fmt.Errorf("%d serial numbers given for %s units", len(m.Serials), qty)
+	}
+	seen := make(map[string]bool, len(m.Serials))
+	for _, number := range m.Serials {
+		if seen[number] {
+			return fmt.Errorf("serial %s is given twice", number)
+		}
+		seen[number] = true
+	}
+	if err := l.moveRepo.Create(m); err != nil {
+		return err
+	}
+
+	for _, number := range m.Serials {
+		s, err := l.serialRepo.Find(m.ProductID, number)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound) && m.Quantity > 0 && isNewSerialSource(m.Type):
+			s = &model.Serial{ProductID: m.ProductID, Number: number, Status: model.SerialInStock, LocationID: &m.LocationID}
+			if err := l.serialRepo.Create(s); err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("serial %s not found", number)
+		case err != nil:
+			return err
+		default:
+			if err := l.moveSerial(s, m); err != nil {
+				return fmt.Errorf("serial %s: %w", number, err)
+			}
+		}
+		if err := l.serialRepo.AddMovement(s.ID, m.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isNewSerialSource(typ string) bool {
+	return typ == model.StockOpening || typ == model.StockReceipt || typ == model.StockAdjustment
+}
+
+func (l *stockLedger) moveSerial(s *model.Serial, m *model.StockMovement) error {
+	from := s.Status
+	if m.Quantity < 0 {
+		if s.Status != model.SerialInStock || s.LocationID == nil || *s.LocationID != m.LocationID {
+			return fmt.Errorf("is %s, not in stock at location %d", s.Status, m.LocationID)
+		}
+		s.Status = serialMoves[m.Type]
+		if s.Status == "" {
+			s.Status = model.SerialRemoved
+		}
+		if s.Status == model.SerialSold {
+			s.OrderID = m.RefID
+		}
+		return l.serialRepo.UpdateState(s, from)
+	}
+
+	ok := false
+	for _, st := range serialSources[m.Type] {
+		ok = ok || st == s.Status
+	}
+	if !ok {
+		return fmt.Errorf("is %s and cannot come in by %s", s.Status, m.Type)
+	}
+	s.Status = model.SerialInStock
+	s.LocationID = &m.LocationID
+	s.OrderID = nil
+	return l.serialRepo.UpdateState(s, from)
+}
+
+// soldSerials keeps those of numbers that are still sold on the order.
+func (l *stockLedger) soldSerials(orderID, productID uint, numbers []string) ([]string, error) {
+	var sold []string
+	for _, number := range numbers {
+		s, err := l.serialRepo.Find(productID, number)
+		if err != nil {
+			return nil, fmt.Errorf("serial %s: %w", number, err)
+		}
+		if s.Status == model.SerialSold && s.OrderID != nil && *s.OrderID == orderID {
+			sold = append(sold, number)
+		}
+	}
+	return sold, nil
+}
+
+// consume uses up qty units from the oldest cost layers and returns what they cost. Units
+// no layer covers are valued at the average cost.
+func (l *stockLedger) consume(p *model.Product, qty model.Quantity) (model.Money, error) {
+	layers, err := l.costRepo.ListOpen(p.ID)
+	if err != nil {
+		return 0, err
+	}
+	var cost model.Money
+	for _, layer := range layers {
+		if qty == 0 {
+			break
+		}
+		take := layer.Remaining
+		if take > qty {
+			take = qty
+		}
+		used := layer.Quantity - layer.Remaining
+		cost += prorate(layer.Cost, used, used+take, layer.Quantity)
+		if err := l.costRepo.SetRemaining(layer.ID, layer.Remaining-take); err != nil {
+			return 0, err
+		}
+		qty -= take
+	}
+	return cost + p.AverageCost.Mul(qty), nil
+}
+
+// sell takes the stock for the lines of a completed order from its selling location, the
+// components of a kit in its place, along with the ingredients their modifiers use, and
+// sets the cost of goods sold of each line and modifier.
+func (l *stockLedger) sell(order *model.Order, lines []model.OrderItem) error {
+	for i := range lines {
+		line := &lines[i]
+		if len(line.Components) > 0 {
+			if err := l.sellComponents(order, line); err != nil {
+				return err
+			}
+		} else {
+			m := model.StockMovement{
+				ProductID:  line.ProductID,
+				LocationID: order.LocationID,
+				Type:       model.StockSale,
+				Quantity:   -line.Quantity,
+				RefType:    "order",
+				RefID:      &order.ID,
+				Serials:    serialNumbers(line.Serials),
+			}
+			if err := l.record(&m); err != nil {
+				return err
+			}
+			line.Cost = -m.Cost
+		}
+		for j := range line.Modifiers {
+			mod := &line.Modifiers[j]
+			if mod.IngredientID == nil {
+				continue
+			}
+			used := model.StockMovement{
+				ProductID:  *mod.IngredientID,
+				LocationID: order.LocationID,
+				Type:       model.StockSale,
+				Quantity:   -mod.IngredientQuantity.Mul(line.Quantity),
+				RefType:    "order",
+				RefID:      &order.ID,
+			}
+			if err := l.record(&used); err != nil {
+				return fmt.Errorf("%s: %w", mod.Name, err)
+			}
+			mod.Cost = -used.Cost
+		}
+	}
+	return nil
+}
+
+// sellComponents takes the components of the kits sold on a line from stock; the line
+// costs what they cost.
+func (l *stockLedger) sellComponents(order *model.Order, line *model.OrderItem) error {
+	line.Cost = 0
+	for i := range line.Components {
+		c := &line.Components[i]
+		m := model.StockMovement{
+			ProductID:  c.ProductID,
+			LocationID: order.LocationID,
+			Type:       model.StockSale,
+			Quantity:   -c.Quantity.Mul(line.Quantity),
+			RefType:    "order",
+			RefID:      &order.ID,
+		}
+		if err := l.record(&m); err != nil {
+			return fmt.Errorf("kit %d: %w", line.ProductID, err)
+		}
+		c.Cost = -m.Cost
+		line.Cost += c.Cost
+	}
+	return nil
+}
+
+// returnComponents puts the components of the kits in (before, after] of a sold line back
+// at the cost they were sold at.
+func (l *stockLedger) returnComponents(order *model.Order, line model.OrderItem, before, after model.Quantity, typ, refType string, refID *uint) error {
+	for _, c := range line.Components {
+		lotID, err := l.soldLot(order.ID, c.ProductID)
+		if err != nil {
+			return err
+		}
+		err = l.recordAt(&model.StockMovement{
+			ProductID:  c.ProductID,
+			LocationID: order.LocationID,
+			Type:       typ,
+			Quantity:   c.Quantity.Mul(after - before),
+			LotID:      lotID,
+			RefType:    refType,
+			RefID:      refID,
+		}, prorate(c.Cost, before, after, line.Quantity))
+		if err != nil {
+			return fmt.Errorf("kit %d: %w", line.ProductID, err)
+		}
+	}
+	return nil
+}
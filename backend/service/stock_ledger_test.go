@@ -0,0 +1,497 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+// fakeCostLayerRepo keeps a product's cost layers in memory, oldest first.
+type fakeCostLayerRepo struct {
+	layers []model.CostLayer
+}
+
+func (r *fakeCostLayerRepo) Create(l *model.CostLayer) error {
+	l.ID = uint(len(r.layers) + 1)
+	r.layers = append(r.layers, *l)
+	return nil
+}
+
+func (r *fakeCostLayerRepo) ListOpen(productID uint) ([]model.CostLayer, error) {
+	var open []model.CostLayer
+	for _, l := range r.layers {
+		if l.ProductID == productID && l.Remaining > 0 {
+			open = append(open, l)
+		}
+	}
+	return open, nil
+}
+
+func (r *fakeCostLayerRepo) SetRemaining(id uint, remaining model.Quantity) error {
+	r.layers[id-1].Remaining = remaining
+	return nil
+}
+
+func (r *fakeCostLayerRepo) remaining() []model.Quantity {
+	var left []model.Quantity
+	for _, l := range r.layers {
+		left = append(left, l.Remaining)
+	}
+	return left
+}
+
+// fakeProductRepo keeps products in memory with the stock the ledger moves and records
+// the average cost it sets.
+type fakeProductRepo struct {
+	repository.ProductRepository
+	products    map[uint]*model.Product
+	averageCost model.Money
+}
+
+func (r *fakeProductRepo) GetByID(id uint) (*model.Product, error) {
+	p, ok := r.products[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (r *fakeProductRepo) ReduceStock(productID uint, qty model.Quantity) error {
+	p, ok := r.products[productID]
+	if !ok || p.Stock < qty {
+		return errors.New("insufficient stock")
+	}
+	p.Stock -= qty
+	return nil
+}
+
+func (r *fakeProductRepo) IncreaseStock(productID uint, qty model.Quantity) error {
+	p, ok := r.products[productID]
+	if !ok {
+		return errors.New("record not found")
+	}
+	p.Stock += qty
+	return nil
+}
+
+func (r *fakeProductRepo) SetAverageCost(productID uint, cost model.Money) error {
+	r.averageCost = cost
+	if p, ok := r.products[productID]; ok {
+		p.AverageCost = cost
+	}
+	return nil
+}
+
+// fakeStockLevelRepo keeps stock levels by product and location.
+type fakeStockLevelRepo struct {
+	repository.StockLevelRepository
+	levels map[[2]uint]model.Quantity
+}
+
+func (r *fakeStockLevelRepo) Reduce(productID, locationID uint, qty model.Quantity) error {
+	key := [2]uint{productID, locationID}
+	if r.levels[key] < qty {
+		return errors.New("insufficient stock at location")
+	}
+	r.levels[key] -= qty
+	return nil
+}
+
+func (r *fakeStockLevelRepo) Increase(productID, locationID uint, qty model.Quantity) error {
+	r.levels[[2]uint{productID, locationID}] += qty
+	return nil
+}
+
+// costLayers builds open layers of product 1 from (quantity, remaining, cost) triples.
+func costLayers(layers ...[3]int64) *fakeCostLayerRepo {
+	r := &fakeCostLayerRepo{}
+	for _, l := range layers {
+		r.Create(&model.CostLayer{ProductID: 1, Quantity: model.Quantity(l[0]), Remaining: model.Quantity(l[1]), Cost: model.Money(l[2])})
+	}
+	return r
+}
+
+func TestConsume(t *testing.T) {
+	tests := []struct {
+		name     string
+		layers   *fakeCostLayerRepo
+		qty      model.Quantity
+		want     model.Money
+		wantLeft []model.Quantity
+	}{
+		{
+			name:     "from the oldest layer",
+			layers:   costLayers([3]int64{10000, 10000, 1000}, [3]int64{10000, 10000, 1500}),
+			qty:      model.Units(4),
+			want:     400,
+			wantLeft: []model.Quantity{6000, 10000},
+		},
+		{
+			name:     "across layers",
+			layers:   costLayers([3]int64{10000, 10000, 1000}, [3]int64{10000, 10000, 1500}),
+			qty:      model.Units(15),
+			want:     1750,
+			wantLeft: []model.Quantity{0, 5000},
+		},
+		{
+			name:     "beyond the layers at average cost",
+			layers:   costLayers([3]int64{10000, 10000, 1000}, [3]int64{10000, 10000, 1500}),
+			qty:      model.Units(25),
+			want:     3100,
+			wantLeft: []model.Quantity{0, 0},
+		},
+		{
+			name:     "used layers skipped",
+			layers:   costLayers([3]int64{10000, 0, 1000}, [3]int64{10000, 10000, 1500}),
+			qty:      model.Units(2),
+			want:     300,
+			wantLeft: []model.Quantity{0, 8000},
+		},
+		{
+			// a third of 100 is 33.33, so the layer's last two units carry the odd cent
+			name:     "rest of a partly used layer",
+			layers:   costLayers([3]int64{3000, 2000, 100}),
+			qty:      model.Units(2),
+			want:     67,
+			wantLeft: []model.Quantity{0},
+		},
+		{
+			name:     "fraction of a unit",
+			layers:   costLayers([3]int64{10000, 10000, 1000}),
+			qty:      500,
+			want:     50,
+			wantLeft: []model.Quantity{9500},
+		},
+		{
+			name:   "no layers",
+			layers: costLayers(),
+			qty:    model.Units(3),
+			want:   360,
+		},
+	}
+	for _, tt := range tests {
+		l := &stockLedger{costRepo: tt.layers}
+		got, err := l.consume(&model.Product{ID: 1, AverageCost: 120}, tt.qty)
+		if err != nil {
+			t.Errorf("%s: consume: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: consume = %d, want %d", tt.name, got, tt.want)
+		}
+		left := tt.layers.remaining()
+		if len(left) != len(tt.wantLeft) {
+			t.Errorf("%s: remaining = %v, want %v", tt.name, left, tt.wantLeft)
+			continue
+		}
+		for i := range left {
+			if left[i] != tt.wantLeft[i] {
+				t.Errorf("%s: remaining = %v, want %v", tt.name, left, tt.wantLeft)
+				break
+			}
+		}
+	}
+}
+
+func TestValueIncoming(t *testing.T) {
+	cost := func(m model.Money) *model.Money { return &m }
+	tests := []struct {
+		name        string
+		stock       model.Quantity // including the movement
+		qty         model.Quantity
+		cost        *model.Money
+		wantCost    model.Money
+		wantAverage model.Money
+	}{
+		{name: "receipt at a known cost", stock: model.Units(10), qty: model.Units(5), cost: cost(600), wantCost: 600, wantAverage: 110},
+		{name: "at the average cost", stock: model.Units(10), qty: model.Units(5), wantCost: 500, wantAverage: 100},
+		{name: "into empty stock", stock: model.Units(5), qty: model.Units(5), cost: cost(600), wantCost: 600, wantAverage: 120},
+		{name: "stock below zero before", stock: model.Units(3), qty: model.Units(5), cost: cost(600), wantCost: 600, wantAverage: 120},
+		{name: "average rounds to the cent", stock: model.Units(3), qty: model.Units(1), cost: cost(101), wantCost: 101, wantAverage: 100},
+	}
+	for _, tt := range tests {
+		layers, products := &fakeCostLayerRepo{}, &fakeProductRepo{}
+		l := &stockLedger{costRepo: layers, prodRepo: products, method: model.CostingFIFO}
+		m := &model.StockMovement{ProductID: 1, Quantity: tt.qty, RefType: "purchase_order"}
+		if err := l.value(&model.Product{ID: 1, Stock: tt.stock, AverageCost: 100}, m, tt.cost); err != nil {
+			t.Errorf("%s: value: %v", tt.name, err)
+			continue
+		}
+		if m.Cost != tt.wantCost {
+			t.Errorf("%s: cost = %d, want %d", tt.name, m.Cost, tt.wantCost)
+		}
+		if products.averageCost != tt.wantAverage {
+			t.Errorf("%s: average cost = %d, want %d", tt.name, products.averageCost, tt.wantAverage)
+		}
+		if len(layers.layers) != 1 || layers.layers[0].Remaining != tt.qty || layers.layers[0].Cost != tt.wantCost {
+			t.Errorf("%s: layers = %+v, want one of %s costing %d", tt.name, layers.layers, tt.qty, tt.wantCost)
+		}
+	}
+}
+
+func TestValueOutgoing(t *testing.T) {
+	tests := []struct {
+		method   string
+		wantCost model.Money
+	}{
+		{model.CostingFIFO, -1750},
+		{model.CostingAverage, -1800},
+	}
+	for _, tt := range tests {
+		layers := costLayers([3]int64{10000, 10000, 1000}, [3]int64{10000, 10000, 1500})
+		l := &stockLedger{costRepo: layers, method: tt.method}
+		m := &model.StockMovement{ProductID: 1, Quantity: -model.Units(15)}
+		if err := l.value(&model.Product{ID: 1, Stock: model.Units(5), AverageCost: 120}, m, nil); err != nil {
+			t.Errorf("%s: value: %v", tt.method, err)
+			continue
+		}
+		if m.Cost != tt.wantCost {
+			t.Errorf("%s: cost = %d, want %d", tt.method, m.Cost, tt.wantCost)
+		}
+		// the layers are used up either way, so switching method later stays consistent
+		if left := layers.remaining(); left[0] != 0 || left[1] != 5000 {
+			t.Errorf("%s: remaining = %v, want [0 5000]", tt.method, left)
+		}
+	}
+}
+
+// fakeLotRepo keeps lots in memory, listing open ones expiring first first as the
+// database does.
+type fakeLotRepo struct {
+	repository.LotRepository
+	lots []model.Lot
+}
+
+func (r *fakeLotRepo) GetByID(id uint) (*model.Lot, error) {
+	for i := range r.lots {
+		if r.lots[i].ID == id {
+			return &r.lots[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeLotRepo) ListByProduct(productID uint) ([]model.Lot, error) {
+	var list []model.Lot
+	for _, lot := range r.lots {
+		if lot.ProductID == productID {
+			list = append(list, lot)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeLotRepo) ListOpen(productID, locationID uint, sellable bool) ([]model.Lot, error) {
+	var list []model.Lot
+	for _, lot := range r.lots {
+		if lot.ProductID != productID || lot.LocationID != locationID || lot.Quantity <= 0 {
+			continue
+		}
+		if sellable && lot.Expired(time.Now()) {
+			continue
+		}
+		list = append(list, lot)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return expiresBefore(list[i], list[j]) })
+	return list, nil
+}
+
+func (r *fakeLotRepo) LastExpiring(ids []uint) (*model.Lot, error) {
+	var last *model.Lot
+	for _, id := range ids {
+		lot, err := r.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if last == nil || !expiresBefore(*lot, *last) {
+			last = lot
+		}
+	}
+	return last, nil
+}
+
+func (r *fakeLotRepo) Reduce(id uint, qty model.Quantity) error {
+	lot, err := r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if lot.Quantity < qty {
+		return errors.New("insufficient stock in lot")
+	}
+	lot.Quantity -= qty
+	return nil
+}
+
+func (r *fakeLotRepo) Increase(id uint, qty model.Quantity) error {
+	lot, err := r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	lot.Quantity += qty
+	return nil
+}
+
+// expiresBefore orders lots by expiry date, those that never expire last, then by id.
+func expiresBefore(a, b model.Lot) bool {
+	switch {
+	case a.ExpiresOn == nil && b.ExpiresOn == nil:
+		return a.ID < b.ID
+	case a.ExpiresOn == nil || b.ExpiresOn == nil:
+		return b.ExpiresOn == nil
+	case !a.ExpiresOn.Equal(*b.ExpiresOn):
+		return a.ExpiresOn.Before(*b.ExpiresOn)
+	}
+	return a.ID < b.ID
+}
+
+type fakeStockMovementRepo struct {
+	repository.StockMovementRepository
+	created []model.StockMovement
+}
+
+func (r *fakeStockMovementRepo) Create(m *model.StockMovement) error {
+	r.created = append(r.created, *m)
+	return nil
+}
+
+func (r *fakeStockMovementRepo) ListByRef(refType string, refID uint) ([]model.StockMovement, error) {
+	var list []model.StockMovement
+	for _, m := range r.created {
+		if m.RefType == refType && m.RefID != nil && *m.RefID == refID {
+			list = append(list, m)
+		}
+	}
+	return list, nil
+}
+
+// lotMove is a movement written against a lot.
+type lotMove struct {
+	lot  uint
+	qty  model.Quantity
+	cost model.Money
+}
+
+func TestApplyLots(t *testing.T) {
+	day := func(n int) *time.Time {
+		y, m, d := time.Now().Date()
+		t := time.Date(y, m, d+n, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	lotID := func(id uint) *uint { return &id }
+	tests := []struct {
+		name    string
+		move    model.StockMovement
+		want    []lotMove
+		wantErr bool
+	}{
+		{
+			name: "sale from the lot expiring first, expiring today included",
+			move: model.StockMovement{Type: model.StockSale, Quantity: -model.Units(4), Cost: -400},
+			want: []lotMove{{2, -model.Units(2), -200}, {1, -model.Units(2), -200}},
+		},
+		{
+			name: "sale across every unexpired lot",
+			move: model.StockMovement{Type: model.StockSale, Quantity: -model.Units(10), Cost: -1000},
+			want: []lotMove{{2, -model.Units(2), -200}, {1, -model.Units(3), -300}, {3, -model.Units(5), -500}},
+		},
+		{
+			name: "cost split rounds so the parts add up",
+			move: model.StockMovement{Type: model.StockSale, Quantity: -model.Units(3), Cost: -100},
+			want: []lotMove{{2, -model.Units(2), -67}, {1, -model.Units(1), -33}},
+		},
+		{
+			name:    "sale never takes expired stock",
+			move:    model.StockMovement{Type: model.StockSale, Quantity: -model.Units(11)},
+			wantErr: true,
+		},
+		{
+			name:    "transfer never takes expired stock",
+			move:    model.StockMovement{Type: model.StockTransferOut, Quantity: -model.Units(11)},
+			wantErr: true,
+		},
+		{
+			name: "adjustment writes expired stock off first",
+			move: model.StockMovement{Type: model.StockAdjustment, Quantity: -model.Units(5), Cost: -500},
+			want: []lotMove{{4, -model.Units(4), -400}, {2, -model.Units(1), -100}},
+		},
+		{
+			name: "sale from a given lot",
+			move: model.StockMovement{Type: model.StockSale, Quantity: -model.Units(1), LotID: lotID(3)},
+			want: []lotMove{{3, -model.Units(1), 0}},
+		},
+		{
+			name:    "more than the given lot holds",
+			move:    model.StockMovement{Type: model.StockSale, Quantity: -model.Units(4), LotID: lotID(1)},
+			wantErr: true,
+		},
+		{
+			name:    "lot of another location",
+			move:    model.StockMovement{Type: model.StockSale, Quantity: -model.Units(1), LotID: lotID(5)},
+			wantErr: true,
+		},
+		{
+			name: "receipt into its lot",
+			move: model.StockMovement{Type: model.StockReceipt, Quantity: model.Units(6), LotID: lotID(1)},
+			want: []lotMove{{1, model.Units(6), 0}},
+		},
+		{
+			name:    "receipt without a lot",
+			move:    model.StockMovement{Type: model.StockReceipt, Quantity: model.Units(6)},
+			wantErr: true,
+		},
+		{
+			name: "refund without a lot goes into the lot expiring last",
+			move: model.StockMovement{Type: model.StockRefund, Quantity: model.Units(1)},
+			want: []lotMove{{3, model.Units(1), 0}},
+		},
+	}
+	for _, tt := range tests {
+		lots := &fakeLotRepo{lots: []model.Lot{
+			{ID: 1, ProductID: 1, LocationID: 1, ExpiresOn: day(10), Quantity: model.Units(3)},
+			{ID: 2, ProductID: 1, LocationID: 1, ExpiresOn: day(0), Quantity: model.Units(2)},
+			{ID: 3, ProductID: 1, LocationID: 1, Quantity: model.Units(5)},
+			{ID: 4, ProductID: 1, LocationID: 1, ExpiresOn: day(-1), Quantity: model.Units(4)},
+			{ID: 5, ProductID: 1, LocationID: 2, Quantity: model.Units(5)},
+		}}
+		moves := &fakeStockMovementRepo{}
+		l := &stockLedger{lotRepo: lots, moveRepo: moves}
+		m := tt.move
+		m.ProductID, m.LocationID = 1, 1
+		err := l.applyLots(&m)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: applyLots succeeded, want an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: applyLots: %v", tt.name, err)
+			continue
+		}
+		var got []lotMove
+		for _, c := range moves.created {
+			got = append(got, lotMove{*c.LotID, c.Quantity, c.Cost})
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: movements = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: movements = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+		for _, w := range tt.want {
+			before := map[uint]model.Quantity{1: model.Units(3), 2: model.Units(2), 3: model.Units(5), 4: model.Units(4)}[w.lot]
+			if lot, _ := lots.GetByID(w.lot); lot.Quantity != before+w.qty {
+				t.Errorf("%s: lot %d holds %s, want %s", tt.name, w.lot, lot.Quantity, before+w.qty)
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type StockTransferService interface {
+	CreateTransfer(input dto.CreateStockTransferDTO, userID uint) (*model.StockTransfer, error)
+	GetTransfer(id uint) (*model.StockTransfer, error)
+	ListTransfers(status string) ([]model.StockTransfer, error)
+	ReceiveTransfer(id uint, userID uint) (*model.StockTransfer, error)
+	CancelTransfer(id uint, userID uint) (*model.StockTransfer, error)
+}
+
+type stockTransferServiceImpl struct {
+	db           *gorm.DB
+	transferRepo repository.StockTransferRepository
+	locationRepo repository.LocationRepository
+}
+
+func NewStockTransferService(db *gorm.DB, str repository.StockTransferRepository, lr repository.LocationRepository) StockTransferService {
+	return &stockTransferServiceImpl{db: db, transferRepo: str, locationRepo: lr}
+}
+
+// dispatch records one ledger movement per transfer line taking its stock out of the
+// source, with the serials sent for that line.
+func (s *stockTransferServiceImpl) dispatch(tx *gorm.DB, t *model.StockTransfer, serials [][]string, userID uint) error {
+	ledger := newStockLedger(tx, userID)
+	for i, it := range t.Items {
+		err := ledger.record(&model.StockMovement{
+			ProductID:  it.ProductID,
+			LocationID: t.FromLocationID,
+			Type:       model.StockTransferOut,
+			Quantity:   -it.Quantity,
+			Serials:    serials[i],
+			RefType:    "stock_transfer",
+			RefID:      &t.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// land puts the stock dispatched into the given location, reversing each movement out.
+// Lots keep their number and expiry date: stock goes back into the lot it left, or into
+// the same lot at the destination. Serials arrive as they were sent.
+func (s *stockTransferServiceImpl) land(tx *gorm.DB, t *model.StockTransfer, locationID uint, userID uint) error {
+	ledger := newStockLedger(tx, userID)
+	txSerialRepo := impl.NewSerialRepoImpl(tx)
+	outs, err := impl.NewStockMovementRepoImpl(tx).ListByRef("stock_transfer", t.ID)
+	if err != nil {
+		return err
+	}
+	for _, out := range outs {
+		if out.Type != model.StockTransferOut {
+			continue
+		}
+		lotID := out.LotID
+		if lotID != nil && locationID != out.LocationID {
+			lot, err := impl.NewLotRepoImpl(tx).GetByID(*lotID)
+			if err != nil {
+				return err
+			}
+			if lotID, err = ledger.lotAt(lot.ProductID, locationID, lot.Number, lot.ExpiresOn); err != nil {
+				return err
+			}
+		}
+		sent, err := txSerialRepo.ListByMovement(out.ID)
+		if err != nil {
+			return err
+		}
+		var serials []string
+		for _, s := range sent {
+			serials = append(serials, s.Number)
+		}
+		err = ledger.record(&model.StockMovement{
+			ProductID:  out.ProductID,
+			LocationID: locationID,
+			Type:       model.StockTransferIn,
+			Quantity:   -out.Quantity,
+			LotID:      lotID,
+			Serials:    serials,
+			RefType:    "stock_transfer",
+			RefID:      &t.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateTransfer dispatches stock: it leaves the source location at once and is in
+// transit until the destination receives it.
+func (s *stockTransferServiceImpl) CreateTransfer(input dto.CreateStockTransferDTO, userID uint) (*model.StockTransfer, error) {
+	if input.FromLocationID == input.ToLocationID {
+		return nil, errors.New("cannot transfer stock to the location it is at")
+	}
+	for _, id := range []uint{input.FromLocationID, input.ToLocationID} {
+		if _, err := findLocation(s.locationRepo, id); err != nil {
+			return nil, err
+		}
+	}
+
+	t := model.StockTransfer{
+		FromLocationID: input.FromLocationID,
+		ToLocationID:   input.ToLocationID,
+		Status:         model.TransferInTransit,
+		Note:           input.Note,
+		CreatedBy:      userID,
+	}
+	serials := make([][]string, 0, len(input.Items))
+	for _, it := range input.Items {
+		t.Items = append(t.Items, model.StockTransferItem{ProductID: it.ProductID, Quantity: it.Quantity})
+		serials = append(serials, it.Serials)
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := impl.NewStockTransferRepoImpl(tx).Create(&t); err != nil {
+			return err
+		}
+		return s.dispatch(tx, &t, serials, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *stockTransferServiceImpl) GetTransfer(id uint) (*model.StockTransfer, error) {
+	return s.transferRepo.GetByID(id)
+}
+
+func (s *stockTransferServiceImpl) ListTransfers(status string) ([]model.StockTransfer, error) {
+	return s.transferRepo.List(status)
+}
+
+// ReceiveTransfer puts the stock in transit into the destination location.
+func (s *stockTransferServiceImpl) ReceiveTransfer(id uint, userID uint) (*model.StockTransfer, error) {
+	return s.finish(id, model.TransferReceived, userID)
+}
+
+// CancelTransfer returns the stock in transit to the source location.
+func (s *stockTransferServiceImpl) CancelTransfer(id uint, userID uint) (*model.StockTransfer, error) {
+	return s.finish(id, model.TransferCancelled, userID)
+}
+
+func (s *stockTransferServiceImpl) finish(id uint, status string, userID uint) (*model.StockTransfer, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txTransferRepo := impl.NewStockTransferRepoImpl(tx)
+		t, err := txTransferRepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if t.Status != model.TransferInTransit {
+			return fmt.Errorf("stock transfer %d is %s and cannot become %s", t.ID, t.Status, status)
+		}
+		// conditional on the status read, so a transfer cannot be both received and cancelled
+		if err := txTransferRepo.UpdateStatus(t.ID, []string{model.TransferInTransit}, status); err != nil {
+			return err
+		}
+		t.Status = status
+
+		locationID := t.FromLocationID
+		if status == model.TransferReceived {
+			locationID = t.ToLocationID
+			now := time.Now()
+			t.ReceivedBy = &userID
+			t.ReceivedAt = &now
+			if err := txTransferRepo.Update(t); err != nil {
+				return err
+			}
+		}
+		return s.land(tx, t, locationID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.transferRepo.GetByID(id)
+}
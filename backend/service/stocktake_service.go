@@ -0,0 +1,284 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type StocktakeService interface {
+	OpenStocktake(input dto.CreateStocktakeDTO, userID uint) (*model.Stocktake, error)
+	GetStocktake(id uint) (*model.Stocktake, error)
+	ListStocktakes(status string) ([]model.Stocktake, error)
+	AddCounts(id uint, input dto.StocktakeCountDTO, userID uint) ([]model.StocktakeCount, error)
+	ListCounts(id uint) ([]model.StocktakeCount, error)
+	Variance(id uint) ([]model.StocktakeLine, error)
+	PostStocktake(id uint, userID uint) (*model.Stocktake, error)
+	CancelStocktake(id uint) (*model.Stocktake, error)
+}
+
+type stocktakeServiceImpl struct {
+	db            *gorm.DB
+	stocktakeRepo repository.StocktakeRepository
+	prodRepo      repository.ProductRepository
+	moveRepo      repository.StockMovementRepository
+	locationRepo  repository.LocationRepository
+	categoryRepo  repository.CategoryRepository
+}
+
+func NewStocktakeService(db *gorm.DB, str repository.StocktakeRepository, pr repository.ProductRepository, mr repository.StockMovementRepository, lr repository.LocationRepository, cr repository.CategoryRepository) StocktakeService {
+	return &stocktakeServiceImpl{db: db, stocktakeRepo: str, prodRepo: pr, moveRepo: mr, locationRepo: lr, categoryRepo: cr}
+}
+
+// OpenStocktake creates a line for every product in scope, the category including its
+// subcategories, with its stock level at the location. Kits and products with variants
+// hold no stock and get no line. The levels are locked while the session is created, so
+// the quantities match the latest stock movement.
+func (s *stocktakeServiceImpl) OpenStocktake(input dto.CreateStocktakeDTO, userID uint) (*model.Stocktake, error) {
+	location, err := findLocation(s.locationRepo, input.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	var categories categoryTree
+	if input.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(*input.CategoryID); err != nil {
+			return nil, fmt.Errorf("category not found: %w", err)
+		}
+		list, err := s.categoryRepo.List()
+		if err != nil {
+			return nil, err
+		}
+		categories = newCategoryTree(list)
+	}
+
+	st := model.Stocktake{
+		LocationID: location.ID,
+		CategoryID: input.CategoryID,
+		Status:     model.StocktakeOpen,
+		Note:       input.Note,
+		OpenedBy:   userID,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		products, err := impl.NewProductRepoImpl(tx).List(0)
+		if err != nil {
+			return err
+		}
+		var inScope []uint
+		for _, p := range products {
+			if input.CategoryID != nil && !categories.within(p.CategoryID, *input.CategoryID) {
+				continue
+			}
+			if p.HasVariants || p.IsKit {
+				continue
+			}
+			inScope = append(inScope, p.ID)
+		}
+		// with the levels locked no stock change at the location is in flight, so every
+		// movement at or below the mark is already in the quantities
+		quantities, err := impl.NewStockLevelRepoImpl(tx).Lock(location.ID, inScope)
+		if err != nil {
+			return err
+		}
+		mark, err := impl.NewStockMovementRepoImpl(tx).LastID()
+		if err != nil {
+			return err
+		}
+
+		st.LedgerMark = mark
+		for _, id := range inScope {
+			st.Lines = append(st.Lines, model.StocktakeLine{
+				ProductID:      id,
+				SystemQuantity: quantities[id],
+				CountMark:      mark,
+			})
+		}
+		return impl.NewStocktakeRepoImpl(tx).Create(&st)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (s *stocktakeServiceImpl) GetStocktake(id uint) (*model.Stocktake, error) {
+	return s.stocktakeRepo.GetByID(id)
+}
+
+func (s *stocktakeServiceImpl) ListStocktakes(status string) ([]model.Stocktake, error) {
+	return s.stocktakeRepo.List(status)
+}
+
+// AddCounts records counts from one device, each to the precision of its product. Each
+// count is stamped with the latest stock movement, read with the counted products' levels
+// locked, so sales made afterwards are not taken as missing stock.
+func (s *stocktakeServiceImpl) AddCounts(id uint, input dto.StocktakeCountDTO, userID uint) ([]model.StocktakeCount, error) {
+	counts := make([]model.StocktakeCount, 0, len(input.Items))
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txStocktakeRepo := impl.NewStocktakeRepoImpl(tx)
+		if err := txStocktakeRepo.Lock(id); err != nil {
+			return err
+		}
+		st, err := txStocktakeRepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		var productIDs []uint
+		for _, line := range st.Lines {
+			for _, it := range input.Items {
+				if it.ProductID == line.ProductID {
+					productIDs = append(productIDs, line.ProductID)
+					break
+				}
+			}
+		}
+		if _, err := impl.NewStockLevelRepoImpl(tx).Lock(st.LocationID, productIDs); err != nil {
+			return err
+		}
+		mark, err := impl.NewStockMovementRepoImpl(tx).LastID()
+		if err != nil {
+			return err
+		}
+		txProdRepo := impl.NewProductRepoImpl(tx)
+		for _, it := range input.Items {
+			p, err := txProdRepo.GetByID(it.ProductID)
+			if err != nil {
+				return fmt.Errorf("product %d: %w", it.ProductID, err)
+			}
+			if err := p.CheckQuantity(it.Quantity); err != nil {
+				return err
+			}
+			if err := txStocktakeRepo.AddCount(id, it.ProductID, it.Quantity, mark); err != nil {
+				return fmt.Errorf("product %d: %w", it.ProductID, err)
+			}
+			c := model.StocktakeCount{
+				StocktakeID: id,
+				ProductID:   it.ProductID,
+				Quantity:    it.Quantity,
+				Device:      input.Device,
+				UserID:      userID,
+				LedgerMark:  mark,
+			}
+			if err := txStocktakeRepo.CreateCount(&c); err != nil {
+				return err
+			}
+			counts = append(counts, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (s *stocktakeServiceImpl) ListCounts(id uint) ([]model.StocktakeCount, error) {
+	if _, err := s.stocktakeRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.stocktakeRepo.ListCounts(id)
+}
+
+// fillVariance sets Expected and Variance on the lines of an open session. Expected is
+// the system quantity plus the movements up to the line's last count; uncounted lines
+// expect the current quantity and have no variance.
+func fillVariance(moveRepo repository.StockMovementRepository, st *model.Stocktake) error {
+	moves, err := moveRepo.ListByLocationSince(st.LocationID, st.LedgerMark)
+	if err != nil {
+		return err
+	}
+	byProduct := make(map[uint][]model.StockMovement)
+	for _, m := range moves {
+		byProduct[m.ProductID] = append(byProduct[m.ProductID], m)
+	}
+	for i := range st.Lines {
+		line := &st.Lines[i]
+		line.Expected = line.SystemQuantity
+		for _, m := range byProduct[line.ProductID] {
+			if line.Counted == nil || m.ID <= line.CountMark {
+				line.Expected += m.Quantity
+			}
+		}
+		line.Variance = 0
+		if line.Counted != nil {
+			line.Variance = *line.Counted - line.Expected
+		}
+	}
+	return nil
+}
+
+// Variance shows each line against system stock: worked out now for an open session, as
+// posted otherwise.
+func (s *stocktakeServiceImpl) Variance(id uint) ([]model.StocktakeLine, error) {
+	st, err := s.stocktakeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if st.Status == model.StocktakeOpen {
+		if err := fillVariance(s.moveRepo, st); err != nil {
+			return nil, err
+		}
+	}
+	return st.Lines, nil
+}
+
+// PostStocktake closes the session and applies every counted line's variance as a
+// stocktake movement, all in one transaction. Uncounted lines are left alone. A variance on
+// a serial-tracked product cannot be posted, as the count does not say which units are
+// missing or found; settle it with an adjustment naming the serials and count again.
+func (s *stocktakeServiceImpl) PostStocktake(id uint, userID uint) (*model.Stocktake, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		txStocktakeRepo := impl.NewStocktakeRepoImpl(tx)
+		txMoveRepo := impl.NewStockMovementRepoImpl(tx)
+		// closing first waits for counts being added and keeps new ones out
+		if err := txStocktakeRepo.UpdateStatus(id, []string{model.StocktakeOpen}, model.StocktakePosted); err != nil {
+			return err
+		}
+		st, err := txStocktakeRepo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := fillVariance(txMoveRepo, st); err != nil {
+			return err
+		}
+
+		ledger := newStockLedger(tx, userID)
+		for i := range st.Lines {
+			line := &st.Lines[i]
+			if err := txStocktakeRepo.UpdateLine(line); err != nil {
+				return err
+			}
+			err := ledger.record(&model.StockMovement{
+				ProductID:  line.ProductID,
+				LocationID: st.LocationID,
+				Type:       model.StockStocktake,
+				Quantity:   line.Variance,
+				RefType:    "stocktake",
+				RefID:      &st.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		st.PostedBy = &userID
+		st.PostedAt = &now
+		return txStocktakeRepo.Update(st)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.stocktakeRepo.GetByID(id)
+}
+
+func (s *stocktakeServiceImpl) CancelStocktake(id uint) (*model.Stocktake, error) {
+	if err := s.stocktakeRepo.UpdateStatus(id, []string{model.StocktakeOpen}, model.StocktakeCancelled); err != nil {
+		return nil, err
+	}
+	return s.stocktakeRepo.GetByID(id)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+type SupplierService interface {
+	CreateSupplier(input dto.CreateSupplierDTO) (*model.Supplier, error)
+	List() ([]model.Supplier, error)
+	GetByID(id uint) (*model.Supplier, error)
+	UpdateSupplier(id uint, input dto.CreateSupplierDTO) (*model.Supplier, error)
+	DeleteSupplier(id uint) error
+}
+
+type supplierServiceImpl struct {
+	supplierRepo repository.SupplierRepository
+}
+
+func NewSupplierService(sr repository.SupplierRepository) SupplierService {
+	return &supplierServiceImpl{supplierRepo: sr}
+}
+
+func (s *supplierServiceImpl) CreateSupplier(input dto.CreateSupplierDTO) (*model.Supplier, error) {
+	sup := model.Supplier{Name: input.Name, Email: input.Email, Phone: input.Phone, Address: input.Address}
+	if err := s.supplierRepo.Create(&sup); err != nil {
+		return nil, err
+	}
+	return &sup, nil
+}
+
+func (s *supplierServiceImpl) List() ([]model.Supplier, error) {
+	return s.supplierRepo.List()
+}
+
+func (s *supplierServiceImpl) GetByID(id uint) (*model.Supplier, error) {
+	return s.supplierRepo.GetByID(id)
+}
+
+func (s *supplierServiceImpl) UpdateSupplier(id uint, input dto.CreateSupplierDTO) (*model.Supplier, error) {
+	sup, err := s.supplierRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	sup.Name = input.Name
+	sup.Email = input.Email
+	sup.Phone = input.Phone
+	sup.Address = input.Address
+	if err := s.supplierRepo.Update(sup); err != nil {
+		return nil, err
+	}
+	return sup, nil
+}
+
+func (s *supplierServiceImpl) DeleteSupplier(id uint) error {
+	return s.supplierRepo.Delete(id)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+// Tax calculation is shared by order creation, refunds and reports so the same line
+// always produces the same figures:
+//
+//   - tax is calculated per order line and each rate is rounded half away from zero
+//     to the cent;
+//   - simple rates are charged on the net amount, compound rates on the net amount
+//     plus every tax applied before them;
+//   - for tax-inclusive prices the net is backed out of the gross first and any
+//     rounding difference is put on the last rate, so net + tax equals the price paid;
+//   - refunds take a share of the stored line figures with prorate, never a fresh
+//     calculation, so a fully refunded line gives back exactly what was charged.
+
+// sortTaxRates orders rates for application: simple rates first, then compound rates
+// by priority, ties broken by id.
+func sortTaxRates(rates []model.TaxRate) []model.TaxRate {
+	sorted := append([]model.TaxRate(nil), rates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Compound != b.Compound {
+			return !a.Compound
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	})
+	return sorted
+}
+
+// calculateLineTax splits a line amount into its net amount and per-rate taxes. amount is
+// the gross when inclusive is set, otherwise the net.
+func calculateLineTax(amount model.Money, rates []model.TaxRate, inclusive bool) (model.Money, []model.OrderItemTax) {
+	if len(rates) == 0 {
+		return amount, nil
+	}
+	rates = sortTaxRates(rates)
+	if !inclusive {
+		return amount, exclusiveTaxes(amount, rates)
+	}
+
+	net := backOutTax(amount, rates)
+	taxes := exclusiveTaxes(net, rates)
+	var sum model.Money
+	for _, t := range taxes {
+		sum += t.Amount
+	}
+	taxes[len(taxes)-1].Amount += amount - net - sum
+	return net, taxes
+}
+
+func exclusiveTaxes(net model.Money, rates []model.TaxRate) []model.OrderItemTax {
+	taxes := make([]model.OrderItemTax, 0, len(rates))
+	applied := model.Money(0)
+	for _, r := range rates {
+		base := net
+		if r.Compound {
+			base = net + applied
+		}
+		amount := r.Rate.Of(base)
+		applied += amount
+		taxes = append(taxes, model.OrderItemTax{
+			TaxRateID: r.ID,
+			Name:      r.Name,
+			Rate:      r.Rate,
+			Amount:    amount,
+		})
+	}
+	return taxes
+}
+
+// backOutTax finds the net amount inside a tax-inclusive gross. The combined factor is
+// (1 + sum of simple rates) * product of (1 + compound rate), computed exactly.
+func backOutTax(gross model.Money, rates []model.TaxRate) model.Money {
+	hundred := int64(model.OneHundredPercent)
+	den := big.NewInt(hundred)
+	var simple int64
+	for _, r := range rates {
+		if !r.Compound {
+			simple += int64(r.Rate)
+		}
+	}
+	num := big.NewInt(hundred + simple)
+	for _, r := range rates {
+		if r.Compound {
+			num.Mul(num, big.NewInt(hundred+int64(r.Rate)))
+			den.Mul(den, big.NewInt(hundred))
+		}
+	}
+	n := new(big.Int).Mul(big.NewInt(int64(gross)), den)
+	return model.Money(divRoundBig(n, num).Int64())
+}
+
+// divRoundBig divides rounding half away from zero; d must be positive.
+func divRoundBig(n, d *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	twice := new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2))
+	if twice.Cmp(d) >= 0 {
+		if n.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// prorate returns the share of total that belongs to the quantities in (before, after]
+// out of whole. Shares are taken as differences of rounded cumulative amounts, so the
+// shares of a fully refunded line always add up to total.
+func prorate(total model.Money, before, after, whole model.Quantity) model.Money {
+	if whole == 0 {
+		return 0
+	}
+	return total.MulRate(int64(after), int64(whole)) - total.MulRate(int64(before), int64(whole))
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/nawodahansani/pos-backend/model"
+)
+
+func TestCalculateLineTax(t *testing.T) {
+	vat := model.TaxRate{ID: 1, Name: "VAT", Rate: 130000}
+	state := model.TaxRate{ID: 2, Name: "State", Rate: 50000}
+	levy := model.TaxRate{ID: 3, Name: "Levy", Rate: 100000, Compound: true}
+	halfA := model.TaxRate{ID: 4, Name: "A", Rate: 75000}
+	halfB := model.TaxRate{ID: 5, Name: "B", Rate: 75000}
+
+	tests := []struct {
+		name      string
+		amount    model.Money
+		rates     []model.TaxRate
+		inclusive bool
+		wantNet   model.Money
+		wantTaxes []model.Money
+	}{
+		{name: "no rates", amount: 1000, wantNet: 1000},
+		{name: "exclusive", amount: 1000, rates: []model.TaxRate{vat}, wantNet: 1000, wantTaxes: []model.Money{130}},
+		{name: "exclusive rounds half up", amount: 20, rates: []model.TaxRate{halfA}, wantNet: 20, wantTaxes: []model.Money{2}},
+		{name: "exclusive rounds half away from zero when negative", amount: -20, rates: []model.TaxRate{halfA}, wantNet: -20, wantTaxes: []model.Money{-2}},
+		{name: "compound after simple", amount: 1000, rates: []model.TaxRate{levy, state}, wantNet: 1000, wantTaxes: []model.Money{50, 105}},
+		{name: "inclusive", amount: 1130, rates: []model.TaxRate{vat}, inclusive: true, wantNet: 1000, wantTaxes: []model.Money{130}},
+		{name: "inclusive uneven", amount: 999, rates: []model.TaxRate{vat}, inclusive: true, wantNet: 884, wantTaxes: []model.Money{115}},
+		{name: "inclusive compound", amount: 1155, rates: []model.TaxRate{state, levy}, inclusive: true, wantNet: 1000, wantTaxes: []model.Money{50, 105}},
+		{name: "inclusive difference on last rate", amount: 100, rates: []model.TaxRate{halfA, halfB}, inclusive: true, wantNet: 87, wantTaxes: []model.Money{7, 6}},
+		{name: "inclusive refund", amount: -1130, rates: []model.TaxRate{vat}, inclusive: true, wantNet: -1000, wantTaxes: []model.Money{-130}},
+	}
+	for _, tt := range tests {
+		net, taxes := calculateLineTax(tt.amount, tt.rates, tt.inclusive)
+		if net != tt.wantNet {
+			t.Errorf("%s: net = %d, want %d", tt.name, net, tt.wantNet)
+		}
+		if len(taxes) != len(tt.wantTaxes) {
+			t.Errorf("%s: %d taxes, want %d", tt.name, len(taxes), len(tt.wantTaxes))
+			continue
+		}
+		for i, tax := range taxes {
+			if tax.Amount != tt.wantTaxes[i] {
+				t.Errorf("%s: tax %d = %d, want %d", tt.name, i, tax.Amount, tt.wantTaxes[i])
+			}
+		}
+	}
+}
+
+// An inclusive price is always split into a net and taxes that add back up to it.
+func TestCalculateLineTaxInclusiveAddsUp(t *testing.T) {
+	rateSets := [][]model.TaxRate{
+		{{ID: 1, Rate: 130000}},
+		{{ID: 1, Rate: 75000}, {ID: 2, Rate: 75000}},
+		{{ID: 1, Rate: 50000}, {ID: 2, Rate: 100000, Compound: true}, {ID: 3, Rate: 25000, Compound: true, Priority: 1}},
+	}
+	for _, rates := range rateSets {
+		for gross := model.Money(-500); gross <= 5000; gross += 7 {
+			net, taxes := calculateLineTax(gross, rates, true)
+			sum := net
+			for _, tax := range taxes {
+				sum += tax.Amount
+			}
+			if sum != gross {
+				t.Fatalf("gross %d with %d rates: net %d and taxes add up to %d", gross, len(rates), net, sum)
+			}
+		}
+	}
+}
+
+func TestSortTaxRates(t *testing.T) {
+	rates := []model.TaxRate{
+		{ID: 1, Compound: true, Priority: 2},
+		{ID: 2},
+		{ID: 3, Compound: true, Priority: 1},
+		{ID: 4, Compound: true, Priority: 1},
+		{ID: 5},
+	}
+	want := []uint{2, 5, 3, 4, 1}
+	for i, r := range sortTaxRates(rates) {
+		if r.ID != want[i] {
+			t.Fatalf("position %d is rate %d, want %d", i, r.ID, want[i])
+		}
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		total                model.Money
+		before, after, whole model.Quantity
+		want                 model.Money
+	}{
+		{1000, 0, model.Units(1), model.Units(3), 333},
+		{1000, model.Units(1), model.Units(2), model.Units(3), 334},
+		{1000, model.Units(2), model.Units(3), model.Units(3), 333},
+		{1000, 0, model.Units(3), model.Units(3), 1000},
+		{-1000, 0, model.Units(1), model.Units(3), -333},
+		{1000, 0, 250, 1500, 167},
+		{1000, 0, model.Units(1), 0, 0},
+	}
+	for _, tt := range tests {
+		if got := prorate(tt.total, tt.before, tt.after, tt.whole); got != tt.want {
+			t.Errorf("prorate(%d, %s, %s, %s) = %d, want %d", int64(tt.total), tt.before, tt.after, tt.whole, got, tt.want)
+		}
+	}
+}
+
+// However a line is refunded in parts, the parts give back exactly what was charged.
+func TestProrateSharesAddUp(t *testing.T) {
+	whole := model.Quantity(7250)
+	parts := [][]model.Quantity{
+		{7250},
+		{1000, 1000, 1000, 1000, 1000, 1000, 1250},
+		{1, 2, 3, 7244},
+		{3625, 3625},
+	}
+	for _, total := range []model.Money{1, 999, 1000, 123457, -4321} {
+		for _, p := range parts {
+			var refunded, before model.Quantity
+			var sum model.Money
+			for _, qty := range p {
+				refunded = before + qty
+				sum += prorate(total, before, refunded, whole)
+				before = refunded
+			}
+			if sum != total {
+				t.Errorf("total %d refunded as %v gives back %d", int64(total), p, sum)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+	impl "github.com/nawodahansani/pos-backend/repository/impl"
+	"gorm.io/gorm"
+)
+
+type TaxService interface {
+	CreateRate(input dto.CreateTaxRateDTO) (*model.TaxRate, error)
+	ListRates() ([]model.TaxRate, error)
+	UpdateRate(id uint, input dto.CreateTaxRateDTO) (*model.TaxRate, error)
+	DeleteRate(id uint) error
+
+	CreateClass(input dto.CreateTaxClassDTO) (*model.TaxClass, error)
+	GetClass(id uint) (*model.TaxClass, error)
+	ListClasses() ([]model.TaxClass, error)
+	UpdateClass(id uint, input dto.CreateTaxClassDTO) (*model.TaxClass, error)
+	DeleteClass(id uint) error
+}
+
+type taxServiceImpl struct {
+	db      *gorm.DB
+	taxRepo repository.TaxRepository
+}
+
+func NewTaxService(db *gorm.DB, tr repository.TaxRepository) TaxService {
+	return &taxServiceImpl{db: db, taxRepo: tr}
+}
+
+func (s *taxServiceImpl) CreateRate(input dto.CreateTaxRateDTO) (*model.TaxRate, error) {
+	r := model.TaxRate{
+		Name:     input.Name,
+		Rate:     input.Rate,
+		Compound: input.Compound,
+		Priority: input.Priority,
+	}
+	if err := s.taxRepo.CreateRate(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *taxServiceImpl) ListRates() ([]model.TaxRate, error) {
+	return s.taxRepo.ListRates()
+}
+
+func (s *taxServiceImpl) UpdateRate(id uint, input dto.CreateTaxRateDTO) (*model.TaxRate, error) {
+	r, err := s.taxRepo.GetRate(id)
+	if err != nil {
+		return nil, err
+	}
+	r.Name = input.Name
+	r.Rate = input.Rate
+	r.Compound = input.Compound
+	r.Priority = input.Priority
+	if err := s.taxRepo.UpdateRate(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *taxServiceImpl) DeleteRate(id uint) error {
+	return s.taxRepo.DeleteRate(id)
+}
+
+func (s *taxServiceImpl) loadRates(ids []uint) ([]model.TaxRate, error) {
+	rates, err := s.taxRepo.GetRates(ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(rates))
+	for _, r := range rates {
+		found[r.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("tax rate %d not found", id)
+		}
+	}
+	return rates, nil
+}
+
+func (s *taxServiceImpl) CreateClass(input dto.CreateTaxClassDTO) (*model.TaxClass, error) {
+	rates, err := s.loadRates(input.RateIDs)
+	if err != nil {
+		return nil, err
+	}
+	c := model.TaxClass{Name: input.Name, Rates: rates}
+	// the class and its rate links are written together
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return impl.NewTaxRepoImpl(tx).CreateClass(&c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *taxServiceImpl) GetClass(id uint) (*model.TaxClass, error) {
+	return s.taxRepo.GetClass(id)
+}
+
+func (s *taxServiceImpl) ListClasses() ([]model.TaxClass, error) {
+	return s.taxRepo.ListClasses()
+}
+
+func (s *taxServiceImpl) UpdateClass(id uint, input dto.CreateTaxClassDTO) (*model.TaxClass, error) {
+	c, err := s.taxRepo.GetClass(id)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.loadRates(input.RateIDs)
+	if err != nil {
+		return nil, err
+	}
+	c.Name = input.Name
+	c.Rates = rates
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return impl.NewTaxRepoImpl(tx).UpdateClass(c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *taxServiceImpl) DeleteClass(id uint) error {
+	return s.taxRepo.DeleteClass(id)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nawodahansani/pos-backend/dto"
+	"github.com/nawodahansani/pos-backend/model"
+	"github.com/nawodahansani/pos-backend/repository"
+)
+
+// setUnit gives p its unit of measure, each when none is given. A product sold each or
+// serial-tracked is counted in whole units, and its pack size and reorder levels must fit
+// its precision. Stock held does not change with the unit, so the unit and precision can
+// only change while there is none.
+func setUnit(p *model.Product, in dto.UnitDTO, hasStock bool) error {
+	if in.Unit == "" {
+		in.Unit = model.UnitEach
+	}
+	if hasStock && (in.Unit != p.Unit || in.Precision != p.Precision) {
+		return errors.New("the unit and precision of a product can only be changed while it has no stock")
+	}
+	if in.Precision != 0 && (in.Unit == model.UnitEach || p.SerialTracked) {
+		return fmt.Errorf("a product sold %s or serial-tracked is counted in whole units", model.UnitEach)
+	}
+	p.Unit = in.Unit
+	p.Precision = in.Precision
+	p.PurchaseUnit = in.PurchaseUnit
+	p.PackSize = in.PackSize
+	for _, q := range []model.Quantity{p.PackSize, p.ReorderPoint, p.ReorderQuantity} {
+		if err := p.CheckQuantity(q); err != nil {
+			return err
+		}
+	}
+	if p.PackSize != 0 && p.PurchaseUnit == "" {
+		return errors.New("a product bought in packs needs a purchase unit")
+	}
+	return nil
+}
+
+// holdsStock reports whether p, or any of its variants, has stock.
+func holdsStock(prodRepo repository.ProductRepository, p *model.Product) (bool, error) {
+	if !p.HasVariants {
+		return p.Stock != 0, nil
+	}
+	variants, err := prodRepo.ListVariants(p.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range variants {
+		if v.Stock != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -43,9 +43,16 @@ interface OrderItemRequest {
   quantity: number;
 }
 
+interface PaymentRequest {
+  tender: "cash" | "card" | "store_credit" | "other";
+  amount: number;
+  reference?: string;
+}
+
 interface CreateOrderRequest {
   customer_id: number;
   items: OrderItemRequest[];
+  payments: PaymentRequest[];
 }
 
 interface ResponseDTO<T> {
@@ -64,6 +71,7 @@ export default function CreateOrderPage() {
   const [orderItems, setOrderItems] = useState<OrderItem[]>([]);
   const [loading, setLoading] = useState(true);
   const [submitting, setSubmitting] = useState(false);
+  const [tendered, setTendered] = useState("");
 
   useEffect(() => {
     async function loadData() {
@@ -193,6 +201,11 @@ export default function CreateOrderPage() {
       alert("Please add items to the order");
       return;
     }
+    const cash = Number(tendered);
+    if (!tendered || !(cash > 0)) {
+      alert("Please enter the cash tendered");
+      return;
+    }
 
     setSubmitting(true);
     try {
@@ -202,7 +215,10 @@ export default function CreateOrderPage() {
         items: orderItems.map(item => ({
           product_id: item.productId,
           quantity: item.quantity
-        }))
+        })),
+        // The backend prices the order, so send what the customer handed over and
+        // let it work out the change
+        payments: [{ tender: "cash", amount: Number(cash.toFixed(2)) }]
       };
 
       console.log("Sending order data to backend:", orderData);
@@ -213,7 +229,8 @@ export default function CreateOrderPage() {
       console.log("Backend response:", response);
       
       if (response.status === "success") {
-        alert("Order created successfully!");
+        const change = Number(response.data?.change_due ?? 0);
+        alert(`Order created successfully! Change due: Rs. ${change.toFixed(2)}`);
         router.push("/orders");
       } else {
         let errorMessage = response.message || "Failed to create order";
@@ -587,6 +604,21 @@ export default function CreateOrderPage() {
                       <span className="text-2xl font-bold text-gray-900">{total.toFixed(2)}</span>
                     </div>
                   </div>
+                  <p className="text-xs text-gray-500 mt-1">
+                    Estimate only; promotions and tax are applied when the order is placed.
+                  </p>
+                </div>
+                <div>
+                  <label className="block text-sm text-gray-600 mb-1">Cash Tendered</label>
+                  <input
+                    type="number"
+                    min="0"
+                    step="0.01"
+                    placeholder="0.00"
+                    className="w-full px-3 py-2.5 border border-gray-300 rounded-lg focus:ring-2 focus:ring-blue-500 focus:border-blue-500 outline-none transition"
+                    value={tendered}
+                    onChange={(e) => setTendered(e.target.value)}
+                  />
                 </div>
               </div>
 
@@ -52,18 +52,13 @@ export default function ProductForm({
     setLoading(true);
 
     try {
-      const productData = { 
-        name, 
-        price: parseFloat(price), 
-        stock: parseInt(stock) 
-      };
-
       let res: ResponseDTO<Product>;
 
       if (initialData) {
-        res = await apiPut(`/products/${initialData.id}`, productData);
+        // stock is changed through stock adjustments, not product edits
+        res = await apiPut(`/products/${initialData.id}`, { name, price: parseFloat(price) });
       } else {
-        res = await apiPost("/products", productData);
+        res = await apiPost("/products", { name, price: parseFloat(price), stock: parseInt(stock) });
       }
 
       onSubmit(res.data);
@@ -163,6 +158,8 @@ export default function ProductForm({
                 placeholder="0"
                 value={stock}
                 onChange={(e) => setStock(e.target.value)}
+                readOnly={!!initialData}
+                title={initialData ? "Use a stock adjustment to change stock" : undefined}
                 required
               />
             </div>
@@ -1,6 +1,15 @@
 export interface OrderItem {  //OrderItemDTO
   product_id: number;
-  quantity: number;
+  barcode?: string;
+  quantity?: number;
+  modifiers?: number[];
+}
+
+export interface OrderItemModifier {
+  id: number;
+  modifier_id: number;
+  name: string;
+  price_delta: number;
 }
 
 export interface Order {
@@ -11,7 +20,14 @@ export interface Order {
   created_at: string;
 }
 
+export interface PaymentDTO {
+  tender: "cash" | "card" | "store_credit" | "other";
+  amount: number;
+  reference?: string;
+}
+
 export interface CreateOrderDTO {
   customer_id: number;
   items: OrderItem[];   //OrderItemDTO[]
+  payments: PaymentDTO[];
 }
@@ -1,12 +1,141 @@
+export interface StockLevel {
+  product_id: number;
+  location_id: number;
+  quantity: number;
+}
+
+export interface Barcode {
+  id?: number;
+  code: string;
+  type: "ean13" | "upca" | "code128" | "scale";
+}
+
+export type Unit = "each" | "kg" | "g" | "l" | "m";
+
+export interface ProductOptionValue {
+  id: number;
+  option_id: number;
+  value: string;
+  position: number;
+}
+
+export interface ProductOption {
+  id: number;
+  product_id: number;
+  name: string;
+  position: number;
+  values: ProductOptionValue[];
+}
+
+export interface Modifier {
+  id: number;
+  group_id: number;
+  name: string;
+  price_delta: number;
+  ingredient_id?: number | null;
+  ingredient_quantity?: number;
+}
+
+export interface ModifierGroup {
+  id: number;
+  name: string;
+  min_select: number;
+  max_select: number;
+  modifiers: Modifier[];
+}
+
 export interface Product {
   id: string;
   name: string;
+  sku?: string;
+  barcodes?: Barcode[];
   price: number;
   stock: number;
+  batch_tracked?: boolean;
+  serial_tracked?: boolean;
+  stock_levels?: StockLevel[];
+  parent_id?: number | null;
+  has_variants?: boolean;
+  price_override?: boolean;
+  options?: ProductOption[];
+  variants?: Product[];
+  option_values?: ProductOptionValue[];
+  is_kit?: boolean;
+  components?: KitComponent[];
+  unit?: Unit;
+  precision?: number;
+  purchase_unit?: string;
+  pack_size?: number;
+}
+
+export interface KitComponent {
+  id: number;
+  kit_id: number;
+  component_id: number;
+  quantity: number;
+  component?: Product;
+}
+
+export interface KitComponentInput {
+  product_id: number;
+  quantity: number;
+}
+
+export interface GenerateVariantsDTO {
+  options: { name: string; values: string[] }[];
+}
+
+export interface Lot {
+  id: number;
+  product_id: number;
+  location_id: number;
+  number: string;
+  expires_on: string | null;
+  quantity: number;
+}
+
+export interface LotInput {
+  number: string;
+  expires_on?: string;
 }
 
 export interface CreateProductDTO {
   name: string;
+  sku?: string;
+  barcodes?: Barcode[];
   price: number;
   stock: number;
+  location_id?: number;
+  batch_tracked?: boolean;
+  serial_tracked?: boolean;
+  lot?: LotInput;
+  serials?: string[];
+  components?: KitComponentInput[];
+  unit?: Unit;
+  precision?: number;
+  purchase_unit?: string;
+  pack_size?: number;
+}
+
+export interface UpdateProductDTO {
+  name: string;
+  sku?: string;
+  barcodes?: Barcode[];
+  price: number;
+  batch_tracked?: boolean;
+  serial_tracked?: boolean;
+  components?: KitComponentInput[];
+  unit?: Unit;
+  precision?: number;
+  purchase_unit?: string;
+  pack_size?: number;
+}
+
+export interface StockAdjustmentDTO {
+  quantity: number;
+  reason: "damage" | "theft" | "found" | "correction";
+  location_id?: number;
+  lot?: LotInput;
+  serials?: string[];
+  note?: string;
 }